@@ -1,15 +1,18 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/cdyfng/coind/cointop"
 	"github.com/spf13/cobra"
 )
 
 // Execute executes the program
 func Execute() {
-	var version, test, clean, reset, hideMarketbar, hideChart, hideStatusbar, onlyTable bool
+	var version, test, clean, reset, fresh, hideMarketbar, hideChart, hideStatusbar, onlyTable bool
 	var refreshRate uint
-	var config, cmcAPIKey, apiChoice, colorscheme, coin, currency string
+	var config, cmcAPIKey, apiChoice, colorscheme, coin, currency, locale string
 
 	var rootCmd = &cobra.Command{
 		Use:   "cointop",
@@ -66,6 +69,7 @@ For more information, visit: https://github.com/cdyfng/coind`,
 				HideStatusbar:       hideStatusbar,
 				OnlyTable:           onlyTable,
 				RefreshRate:         refreshRateP,
+				Fresh:               fresh,
 			})
 			if err != nil {
 				return err
@@ -79,6 +83,7 @@ For more information, visit: https://github.com/cdyfng/coind`,
 	rootCmd.Flags().BoolVarP(&test, "test", "", false, "Run test (for Homebrew)")
 	rootCmd.Flags().BoolVarP(&clean, "clean", "", false, "Wipe clean the cache")
 	rootCmd.Flags().BoolVarP(&reset, "reset", "", false, "Reset the config. Make sure to backup any relevant changes first!")
+	rootCmd.Flags().BoolVarP(&fresh, "fresh", "", false, "Ignore the sort, page, coin, chart and view of the last session")
 	rootCmd.Flags().BoolVarP(&hideMarketbar, "hide-marketbar", "", false, "Hide the top marketbar")
 	rootCmd.Flags().BoolVarP(&hideChart, "hide-chart", "", false, "Hide the chart view")
 	rootCmd.Flags().BoolVarP(&hideStatusbar, "hide-statusbar", "", false, "Hide the bottom statusbar")
@@ -124,13 +129,58 @@ For more information, visit: https://github.com/cdyfng/coind`,
 		Long:  `The price command display the current price of a coin`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cointop.PrintPrice(&cointop.PriceConfig{
-				Coin:      coin,
-				Currency:  currency,
-				APIChoice: apiChoice,
+				Coin:           coin,
+				Currency:       currency,
+				APIChoice:      apiChoice,
+				Locale:         locale,
+				ConfigFilepath: config,
 			})
 		},
 	}
 
+	var keysCmd = &cobra.Command{
+		Use:   "keys [filter]",
+		Short: "Lists the actions and their shortcut keys",
+		Long:  `The keys command lists the actions grouped by category with their shortcut keys and descriptions, optionally filtered by a search term`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var filter string
+			if len(args) > 0 {
+				filter = args[0]
+			}
+
+			return cointop.PrintKeys(&cointop.KeysConfig{
+				ConfigFilepath: config,
+				Filter:         filter,
+			})
+		},
+	}
+
+	keysCmd.Flags().StringVarP(&config, "config", "c", "", "Config filepath. (default ~/.cointop/config.toml)")
+
+	var colorsCmd = &cobra.Command{
+		Use:   "colors",
+		Short: "Colorscheme commands",
+		Long:  `The colors command has commands to work with colorscheme files`,
+	}
+
+	var colorsValidateCmd = &cobra.Command{
+		Use:   "validate <file>",
+		Short: "Validates a colorscheme file",
+		Long:  `The validate command reports the unknown keys and invalid values of a colorscheme file, or of an installed colorscheme by name, with their line numbers`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := cointop.ValidateColorscheme(&cointop.ColorsValidateConfig{
+				Filepath: args[0],
+			}); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	colorsCmd.AddCommand(colorsValidateCmd)
+
 	var testCmd = &cobra.Command{
 		Use:   "test",
 		Short: "Runs tests",
@@ -143,8 +193,10 @@ For more information, visit: https://github.com/cdyfng/coind`,
 	priceCmd.Flags().StringVarP(&coin, "coin", "c", "bitcoin", "Full name of the coin (default \"bitcoin\")")
 	priceCmd.Flags().StringVarP(&currency, "currency", "f", "USD", "The currency to convert to (default \"USD\")")
 	priceCmd.Flags().StringVarP(&apiChoice, "api", "a", cointop.CoinGecko, "API choice. Available choices are \"coinmarketcap\" and \"coingecko\"")
+	priceCmd.Flags().StringVarP(&locale, "locale", "l", "", "Locale of the number format, e.g. \"de-DE\" (default is the locale of the config file)")
+	priceCmd.Flags().StringVarP(&config, "config", "", "", "Config filepath. (default ~/.cointop/config.toml)")
 
-	rootCmd.AddCommand(versionCmd, cleanCmd, resetCmd, priceCmd, testCmd)
+	rootCmd.AddCommand(versionCmd, cleanCmd, resetCmd, priceCmd, keysCmd, colorsCmd, testCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		panic(err)
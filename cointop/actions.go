@@ -1,62 +1,128 @@
 package cointop
 
+// ActionInfo is the help description of an action and the category it's listed under
+type ActionInfo struct {
+	Category    string
+	Description string
+}
+
+// actionCategories are the action categories in the order they're listed in the help
+var actionCategories = []string{
+	"Navigation",
+	"Sorting",
+	"Search and filter",
+	"Favorites and watchlists",
+	"Portfolio",
+	"Chart",
+	"Menus",
+	"General",
+}
+
+// ActionInfos returns the help descriptions of all the available actions
+func ActionInfos() map[string]ActionInfo {
+	return map[string]ActionInfo{
+		"move_up":                           {"Navigation", "Move one row up"},
+		"move_down":                         {"Navigation", "Move one row down"},
+		"move_up_or_previous_page":          {"Navigation", "Move one row up or to previous page if at first row"},
+		"move_down_or_next_page":            {"Navigation", "Move one row down or to next page if at last row"},
+		"page_up":                           {"Navigation", "Scroll one page up"},
+		"page_down":                         {"Navigation", "Scroll one page down"},
+		"previous_page":                     {"Navigation", "Go to previous page"},
+		"next_page":                         {"Navigation", "Go to next page"},
+		"first_page":                        {"Navigation", "Go to first page"},
+		"last_page":                         {"Navigation", "Go to last page"},
+		"move_to_page_first_row":            {"Navigation", "Move to first row on page"},
+		"move_to_page_last_row":             {"Navigation", "Move to last row on page"},
+		"move_to_page_visible_first_row":    {"Navigation", "Move to first visible row on page"},
+		"move_to_page_visible_middle_row":   {"Navigation", "Move to middle visible row on page"},
+		"move_to_page_visible_last_row":     {"Navigation", "Move to last visible row on page"},
+		"open_link":                         {"Navigation", "Open row link"},
+		"sort_column_rank":                  {"Sorting", "Sort table by column rank"},
+		"sort_column_name":                  {"Sorting", "Sort table by column name"},
+		"sort_column_symbol":                {"Sorting", "Sort table by column symbol"},
+		"sort_column_price":                 {"Sorting", "Sort table by column price"},
+		"sort_column_holdings":              {"Sorting", "Sort table by column holdings"},
+		"sort_column_balance":               {"Sorting", "Sort table by column balance"},
+		"sort_column_market_cap":            {"Sorting", "Sort table by column market cap"},
+		"sort_column_24h_volume":            {"Sorting", "Sort table by column 24 hour volume"},
+		"sort_column_1h_change":             {"Sorting", "Sort table by column 1 hour change"},
+		"sort_column_24h_change":            {"Sorting", "Sort table by column 24 hour change"},
+		"sort_column_7d_change":             {"Sorting", "Sort table by column 7 day change"},
+		"sort_column_30d_change":            {"Sorting", "Sort table by column 30 day change"},
+		"sort_column_1y_change":             {"Sorting", "Sort table by column 1 year change"},
+		"sort_column_total_supply":          {"Sorting", "Sort table by column total supply"},
+		"sort_column_available_supply":      {"Sorting", "Sort table by column available supply"},
+		"sort_column_last_updated":          {"Sorting", "Sort table by column last updated"},
+		"sort_column_asc":                   {"Sorting", "Sort highlighted column by ascending order"},
+		"sort_column_desc":                  {"Sorting", "Sort highlighted column by descending order"},
+		"sort_left_column":                  {"Sorting", "Sort the column to the left of the highlighted column"},
+		"sort_right_column":                 {"Sorting", "Sort the column to the right of the highlighted column"},
+		"open_search":                       {"Search and filter", "Open search field"},
+		"next_search_match":                 {"Search and filter", "Go to next search match"},
+		"previous_search_match":             {"Search and filter", "Go to previous search match"},
+		"open_filter":                       {"Search and filter", "Open filter field"},
+		"clear_filter":                      {"Search and filter", "Clear table filter"},
+		"open_command_palette":              {"Search and filter", "Open command palette to run an action or command"},
+		"toggle_favorite":                   {"Favorites and watchlists", "Toggle coin as favorite"},
+		"toggle_show_favorites":             {"Favorites and watchlists", "Toggle show favorites"},
+		"show_watchlists_menu":              {"Favorites and watchlists", "Show watchlists menu to add or remove the highlighted coin"},
+		"toggle_watchlists_menu":            {"Favorites and watchlists", "Toggle watchlists menu"},
+		"next_watchlist":                    {"Favorites and watchlists", "Cycle table through watchlists"},
+		"toggle_portfolio":                  {"Portfolio", "Toggle portfolio view"},
+		"toggle_show_portfolio":             {"Portfolio", "Toggle show portfolio view"},
+		"show_portfolio_edit_menu":          {"Portfolio", "Show portfolio edit holdings menu"},
+		"toggle_row_chart":                  {"Chart", "Toggle the chart for the highlighted row"},
+		"previous_chart_range":              {"Chart", "Select previous chart date range (e.g. 7D → 3D)"},
+		"next_chart_range":                  {"Chart", "Select next chart date range (e.g. 3D → 7D)"},
+		"first_chart_range":                 {"Chart", "Select first chart date range (e.g. 1H)"},
+		"last_chart_range":                  {"Chart", "Select last chart date range (e.g. All Time)"},
+		"enlarge_chart":                     {"Chart", "Increase chart height"},
+		"shorten_chart":                     {"Chart", "Decrease chart height"},
+		"toggle_chart_mode":                 {"Chart", "Toggle chart between line and candlestick mode"},
+		"next_chart_scale":                  {"Chart", "Switch the chart y-axis to the next scale (linear → log → percent)"},
+		"toggle_chart_inspect":              {"Chart", "Toggle the chart inspect cursor"},
+		"toggle_chart_sma":                  {"Chart", "Toggle simple moving average overlay on the chart"},
+		"toggle_chart_ema":                  {"Chart", "Toggle exponential moving average overlay on the chart"},
+		"toggle_chart_bollinger":            {"Chart", "Toggle Bollinger bands overlay on the chart"},
+		"toggle_chart_rsi":                  {"Chart", "Toggle RSI indicator panel under the chart"},
+		"toggle_chart_macd":                 {"Chart", "Toggle MACD indicator panel under the chart"},
+		"enlarge_volume_chart":              {"Chart", "Increase volume chart height"},
+		"shorten_volume_chart":              {"Chart", "Decrease volume chart height"},
+		"toggle_compare_coin":               {"Chart", "Mark or unmark coin for the compare chart"},
+		"toggle_compare_chart":              {"Chart", "Toggle between the compare chart and the coin chart"},
+		"clear_compare_coins":               {"Chart", "Unmark all the coins of the compare chart"},
+		"show_currency_convert_menu":        {"Menus", "Show currency convert menu"},
+		"hide_currency_convert_menu":        {"Menus", "Hide currency convert menu"},
+		"toggle_show_currency_convert_menu": {"Menus", "Toggle show currency convert menu"},
+		"show_table_columns_menu":           {"Menus", "Show table columns menu"},
+		"show_colorscheme_menu":             {"Menus", "Show colorscheme menu to preview and select a colorscheme"},
+		"toggle_colorscheme_menu":           {"Menus", "Toggle colorscheme menu"},
+		"show_top_movers":                   {"Menus", "Show top movers panel of the biggest gainers, losers and rank changes"},
+		"toggle_top_movers":                 {"Menus", "Toggle top movers panel"},
+		"show_calculator":                   {"Menus", "Show calculator to convert between coins and currencies"},
+		"toggle_calculator":                 {"Menus", "Toggle calculator"},
+		"toggle_show_table_columns_menu":    {"Menus", "Toggle show table columns menu"},
+		"help":                              {"General", "Show help"},
+		"toggle_show_help":                  {"General", "Toggle show help"},
+		"show_help":                         {"General", "Show help"},
+		"hide_help":                         {"General", "Hide help"},
+		"close_help":                        {"General", "Hide help"},
+		"refresh":                           {"General", "Do a manual refresh on the data"},
+		"save":                              {"General", "Save config"},
+		"toggle_table_fullscreen":           {"General", "Toggle table fullscreen"},
+		"quit":                              {"General", "Quit application"},
+		"quit_view":                         {"General", "Quit view"},
+	}
+}
+
 // ActionsMap returns a map of all the available actions
 func ActionsMap() map[string]bool {
-	return map[string]bool{
-		"first_page":                        true,
-		"help":                              true,
-		"toggle_show_help":                  true,
-		"close_help":                        true,
-		"last_page":                         true,
-		"move_to_page_first_row":            true,
-		"move_to_page_last_row":             true,
-		"move_to_page_visible_first_row":    true,
-		"move_to_page_visible_last_row":     true,
-		"move_to_page_visible_middle_row":   true,
-		"move_up":                           true,
-		"move_down":                         true,
-		"next_page":                         true,
-		"open_link":                         true,
-		"page_down":                         true,
-		"page_up":                           true,
-		"previous_page":                     true,
-		"quit":                              true,
-		"quit_view":                         true,
-		"refresh":                           true,
-		"sort_column_1h_change":             true,
-		"sort_column_24h_change":            true,
-		"sort_column_24h_volume":            true,
-		"sort_column_7d_change":             true,
-		"sort_column_30d_change":            true,
-		"sort_column_1y_change":            true,
-		"sort_column_asc":                   true,
-		"sort_column_available_supply":      true,
-		"sort_column_desc":                  true,
-		"sort_column_last_updated":          true,
-		"sort_column_market_cap":            true,
-		"sort_column_name":                  true,
-		"sort_column_price":                 true,
-		"sort_column_rank":                  true,
-		"sort_column_symbol":                true,
-		"sort_column_total_supply":          true,
-		"sort_left_column":                  true,
-		"sort_right_column":                 true,
-		"toggle_row_chart":                  true,
-		"open_search":                       true,
-		"toggle_favorite":                   true,
-		"toggle_show_favorites":             true,
-		"previous_chart_range":              true,
-		"next_chart_range":                  true,
-		"first_chart_range":                 true,
-		"last_chart_range":                  true,
-		"toggle_show_currency_convert_menu": true,
-		"show_currency_convert_menu":        true,
-		"hide_currency_convert_menu":        true,
-		"toggle_portfolio":                  true,
-		"toggle_show_portfolio":             true,
-		"enlarge_chart":                     true,
-		"shorten_chart":                     true,
+	actions := make(map[string]bool)
+	for action := range ActionInfos() {
+		actions[action] = true
 	}
+
+	return actions
 }
 
 // ActionExists returns true if action exists
@@ -71,6 +71,9 @@ type LinksItem map[string]interface{}
 // ChartItem ...
 type ChartItem [2]float32
 
+// OHLCItem ...
+type OHLCItem [5]float64
+
 // MarketDataItem map all market data item
 type MarketDataItem struct {
 	CurrentPrice                           AllCurrencies     `json:"current_price"`
@@ -73,6 +73,10 @@ type CoinsIDMarketChart struct {
 	TotalVolumes *[]ChartItem `json:"total_volumes"`
 }
 
+// CoinsIDOHLC https://api.coingecko.com/api/v3/coins/bitcoin/ohlc?vs_currency=usd&days=1
+// Each item is [timestamp, open, high, low, close]
+type CoinsIDOHLC []OHLCItem
+
 // CoinsIDStatusUpdates
 
 // CoinsIDContractAddress https://api.coingecko.com/api/v3/coins/{id}/contract/{contract_address}
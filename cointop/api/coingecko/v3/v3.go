@@ -287,6 +287,31 @@ func (c *Client) CoinsIDMarketChart(id string, vsCurrency string, days string) (
 	return &m, nil
 }
 
+// CoinsIDOHLC /coins/{id}/ohlc?vs_currency={usd, eur, jpy, etc.}&days={1,7,14,30,90,180,365,max}
+func (c *Client) CoinsIDOHLC(id string, vsCurrency string, days string) (*types.CoinsIDOHLC, error) {
+	if len(id) == 0 || len(vsCurrency) == 0 || len(days) == 0 {
+		return nil, fmt.Errorf("id, vsCurrency, and days is required")
+	}
+
+	params := url.Values{}
+	params.Add("vs_currency", vsCurrency)
+	params.Add("days", days)
+
+	url := fmt.Sprintf("%s/coins/%s/ohlc?%s", baseURL, id, params.Encode())
+	resp, err := c.MakeReq(url)
+	if err != nil {
+		return nil, err
+	}
+
+	m := types.CoinsIDOHLC{}
+	err = json.Unmarshal(resp, &m)
+	if err != nil {
+		return &m, err
+	}
+
+	return &m, nil
+}
+
 // CoinsIDStatusUpdates
 
 // CoinsIDContractAddress https://api.coingecko.com/api/v3/coins/{id}/contract/{contract_address}
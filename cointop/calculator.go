@@ -0,0 +1,445 @@
+package cointop
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cdyfng/coind/cointop/common/pad"
+	"github.com/miguelmota/gocui"
+)
+
+// calculatorInputWidth is the width of the input field of the calculator
+const calculatorInputWidth = 48
+
+// calculatorConnectives are the words which may separate the unit to convert from and the unit to convert to
+var calculatorConnectives = map[string]bool{
+	"in": true,
+	"to": true,
+	"as": true,
+	"=":  true,
+}
+
+// CalculatorView is structure for calculator view
+type CalculatorView struct {
+	*View
+}
+
+// NewCalculatorView returns a new calculator view
+func NewCalculatorView() *CalculatorView {
+	return &CalculatorView{NewView("calculator")}
+}
+
+// CalculatorInputView is structure for the input field of the calculator view
+type CalculatorInputView struct {
+	*View
+}
+
+// NewCalculatorInputView returns a new calculator input view
+func NewCalculatorInputView() *CalculatorInputView {
+	return &CalculatorInputView{NewView("calculatorinput")}
+}
+
+// calculatorQuery is the parsed calculator input, e.g. "0.37 ETH in EUR". The units are empty when left out
+type calculatorQuery struct {
+	Amount float64
+	From   string
+	To     string
+}
+
+// calculatorUnit is a coin or a currency the calculator converts between
+type calculatorUnit struct {
+	Coin     *Coin
+	Currency string
+}
+
+// calculatorResult is a conversion of the calculator
+type calculatorResult struct {
+	Amount float64
+	Value  float64
+	Rate   float64
+	From   *calculatorUnit
+	To     *calculatorUnit
+}
+
+// Symbol returns the symbol of the coin or the code of the currency
+func (unit *calculatorUnit) Symbol() string {
+	if unit.Coin != nil {
+		return unit.Coin.Symbol
+	}
+
+	return unit.Currency
+}
+
+// parseCalculatorInput parses an amount followed by the unit to convert from and the unit to convert to,
+// e.g. "0.37 ETH in EUR", "$5,000 to SOL" or "2.5 btc". The amount may be prefixed with a currency symbol.
+// The decimal separator is the one of the locale, the other separators are ignored
+func parseCalculatorInput(input string, decimal rune) (*calculatorQuery, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return nil, errors.New("enter an amount, e.g. 0.37 ETH in EUR")
+	}
+
+	// NOTE: split the amount from a currency symbol before it or a unit after it, e.g. "$5,000" or "5000usd"
+	first := fields[0]
+	start := strings.IndexFunc(first, func(r rune) bool {
+		return unicode.IsDigit(r) || r == decimal || r == '-'
+	})
+	if start < 0 {
+		return nil, fmt.Errorf("invalid amount %q", first)
+	}
+	end := start + strings.IndexFunc(first[start:], func(r rune) bool {
+		return !unicode.IsDigit(r) && r != decimal && r != ',' && r != '.' && r != '\'' && r != '-'
+	})
+	if end < start {
+		end = len(first)
+	}
+
+	number := strings.Map(func(r rune) rune {
+		switch {
+		case r == decimal:
+			return '.'
+		case unicode.IsDigit(r) || r == '-':
+			return r
+		}
+		return -1
+	}, first[start:end])
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %q", first[start:end])
+	}
+
+	var words []string
+	if rest := first[end:]; rest != "" {
+		words = append(words, rest)
+	}
+	words = append(words, fields[1:]...)
+
+	query := &calculatorQuery{Amount: amount}
+	if symbol := first[:start]; symbol != "" {
+		query.From = currencyBySymbol(symbol)
+		if query.From == "" {
+			return nil, fmt.Errorf("unknown currency symbol %q", symbol)
+		}
+	}
+
+	var from, to []string
+	connective := false
+	for _, word := range words {
+		if calculatorConnectives[strings.ToLower(word)] {
+			connective = true
+			continue
+		}
+		if connective || (query.From != "" && len(from) == 0) {
+			to = append(to, word)
+		} else {
+			from = append(from, word)
+		}
+	}
+
+	// NOTE: without a connective the first word is the unit to convert from and the rest the unit to convert to
+	if !connective && len(from) > 1 {
+		to = from[1:]
+		from = from[:1]
+	}
+	if len(from) > 0 {
+		query.From = strings.Join(from, " ")
+	}
+	query.To = strings.Join(to, " ")
+
+	return query, nil
+}
+
+// currencyBySymbol returns the currency of the symbol, e.g. EUR for €. The dollar sign is the US dollar
+func currencyBySymbol(symbol string) string {
+	if symbol == "$" {
+		return "USD"
+	}
+
+	var currencies []string
+	for currency := range currencySymbolMap {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		if currencySymbolMap[currency] == symbol {
+			return currency
+		}
+	}
+
+	return ""
+}
+
+// calculatorUnit returns the coin or the currency of the name. Coins are matched by symbol, the one
+// with the highest rank first, then by name
+func (ct *Cointop) calculatorUnit(name string) (*calculatorUnit, error) {
+	var match *Coin
+	for _, coin := range ct.State.allCoins {
+		if coin != nil && strings.EqualFold(coin.Symbol, name) && (match == nil || coin.Rank < match.Rank) {
+			match = coin
+		}
+	}
+	if match == nil {
+		for _, coin := range ct.State.allCoins {
+			if coin != nil && strings.EqualFold(coin.Name, name) {
+				match = coin
+				break
+			}
+		}
+	}
+	if match != nil {
+		return &calculatorUnit{Coin: match}, nil
+	}
+
+	currency := strings.ToUpper(name)
+	if _, ok := fiatCurrencyNames[currency]; ok || currency == ct.State.currencyConversion {
+		return &calculatorUnit{Currency: currency}, nil
+	}
+
+	return nil, fmt.Errorf("unknown coin or currency %q", name)
+}
+
+// calculatorUnitPrice returns the price of one unit in the primary currency
+func (ct *Cointop) calculatorUnitPrice(unit *calculatorUnit) (float64, error) {
+	if unit.Coin != nil {
+		if unit.Coin.Price == 0 {
+			return 0, fmt.Errorf("no price for %s", unit.Coin.Symbol)
+		}
+		return unit.Coin.Price, nil
+	}
+	if unit.Currency == ct.State.currencyConversion {
+		return 1, nil
+	}
+
+	rate, err := ct.exchangeRate(ct.State.currencyConversion, unit.Currency)
+	if err != nil {
+		return 0, err
+	}
+	if rate == 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", unit.Currency)
+	}
+
+	return 1 / rate, nil
+}
+
+// calculate converts the amount of the query. The unit to convert from defaults to the highlighted coin
+// and the unit to convert to defaults to the primary currency
+func (ct *Cointop) calculate(query *calculatorQuery) (*calculatorResult, error) {
+	ct.debuglog("calculate()")
+	from := query.From
+	if from == "" {
+		coin := ct.HighlightedRowCoin()
+		if coin == nil {
+			return nil, errors.New("enter a coin or currency to convert from")
+		}
+		from = coin.Symbol
+	}
+	to := query.To
+	if to == "" {
+		to = ct.State.currencyConversion
+	}
+
+	fromUnit, err := ct.calculatorUnit(from)
+	if err != nil {
+		return nil, err
+	}
+	toUnit, err := ct.calculatorUnit(to)
+	if err != nil {
+		return nil, err
+	}
+	fromPrice, err := ct.calculatorUnitPrice(fromUnit)
+	if err != nil {
+		return nil, err
+	}
+	toPrice, err := ct.calculatorUnitPrice(toUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &calculatorResult{
+		Amount: query.Amount,
+		Value:  query.Amount * fromPrice / toPrice,
+		Rate:   fromPrice / toPrice,
+		From:   fromUnit,
+		To:     toUnit,
+	}, nil
+}
+
+// formatCalculatorAmount returns the amount of the unit, with two decimals for currencies and up to
+// eight for coins
+func (ct *Cointop) formatCalculatorAmount(amount float64, unit *calculatorUnit) string {
+	if unit.Coin == nil {
+		return ct.formatCurrency(currencySymbol(unit.Currency), ct.formatNumberN(amount, 2))
+	}
+
+	return fmt.Sprintf("%s %s", ct.formatNumber(math.Round(amount*1e8)/1e8), unit.Coin.Symbol)
+}
+
+// calculatorPortfolioEntry returns the coin and holdings the result copies to the portfolio, which is
+// the coin converted to, or else the coin converted from
+func (result *calculatorResult) calculatorPortfolioEntry() (*Coin, float64) {
+	if result.To.Coin != nil {
+		return result.To.Coin, math.Round(result.Value*1e8) / 1e8
+	}
+	if result.From.Coin != nil {
+		return result.From.Coin, result.Amount
+	}
+
+	return nil, 0
+}
+
+// calculatorEditor converts the input as it's typed
+func (ct *Cointop) calculatorEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+	ct.updateCalculator(v.Buffer())
+}
+
+// decimalSeparator returns the decimal separator of the locale
+func (ct *Cointop) decimalSeparator() rune {
+	s := ct.formatNumberN(0.5, 1)
+	r, _ := utf8.DecodeRuneInString(s[1:])
+	return r
+}
+
+// updateCalculator converts the input and renders the result. Exchange rates may have to be fetched
+// so the conversion runs in the background, and only the result of the latest input is rendered
+func (ct *Cointop) updateCalculator(input string) {
+	ct.debuglog("updateCalculator()")
+	ct.State.calculatorSeq++
+	seq := ct.State.calculatorSeq
+	go func() {
+		query, err := parseCalculatorInput(input, ct.decimalSeparator())
+		var result *calculatorResult
+		if err == nil {
+			result, err = ct.calculate(query)
+		}
+
+		ct.Update(func() error {
+			if seq != ct.State.calculatorSeq || ct.Views.Calculator.Backing() == nil {
+				return nil
+			}
+
+			ct.State.calculatorResult = result
+			ct.Views.Calculator.Backing().Clear()
+			ct.Views.Calculator.Backing().Frame = true
+			fmt.Fprintln(ct.Views.Calculator.Backing(), ct.calculatorContent(result, err))
+			return nil
+		})
+	}()
+}
+
+// calculatorContent returns the text of the calculator view with the result of the conversion or its error
+func (ct *Cointop) calculatorContent(result *calculatorResult, err error) string {
+	title := "Calculator"
+	header := ct.colorscheme.MenuHeader(fmt.Sprintf(" %s %s\n\n", title, pad.Left("[esc] close ", ct.ClampedWidth()-len(title)-1, " ")))
+	helpline := " Convert between coins and currencies, e.g. \"0.37 ETH in EUR\" or \"$5,000 to SOL\"\n"
+
+	// NOTE: leave room for the input field
+	body := "\n\n\n\n"
+	actions := " [ESC] Close"
+	if err != nil {
+		body = fmt.Sprintf("%s %s\n", body, err)
+	} else {
+		body = fmt.Sprintf(
+			"%s %s = %s\n\n 1 %s = %s\n",
+			body,
+			ct.formatCalculatorAmount(result.Amount, result.From),
+			ct.colorscheme.MenuLabel(ct.formatCalculatorAmount(result.Value, result.To)),
+			result.From.Symbol(),
+			ct.formatCalculatorAmount(result.Rate, result.To),
+		)
+		if coin, holdings := result.calculatorPortfolioEntry(); coin != nil {
+			actions = fmt.Sprintf(" [Enter] Set %s portfolio holdings to %s   %s", coin.Symbol, ct.formatNumber(holdings), actions)
+		}
+	}
+
+	return fmt.Sprintf("%s%s%s\n%s", header, helpline, body, actions)
+}
+
+func (ct *Cointop) showCalculator() error {
+	ct.debuglog("showCalculator()")
+	input := ""
+	if coin := ct.HighlightedRowCoin(); coin != nil {
+		input = fmt.Sprintf("1 %s", coin.Symbol)
+	}
+
+	ct.State.calculatorVisible = true
+	ct.State.calculatorResult = nil
+	ct.SetActiveView(ct.Views.Calculator.Name())
+	ct.Update(func() error {
+		if ct.Views.CalculatorInput.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.CalculatorInput.Backing().Clear()
+		ct.Views.CalculatorInput.Backing().SetOrigin(0, 0)
+		ct.Views.CalculatorInput.Backing().SetCursor(len(input), 0)
+		fmt.Fprint(ct.Views.CalculatorInput.Backing(), input)
+		return nil
+	})
+	ct.updateCalculator(input)
+	return nil
+}
+
+func (ct *Cointop) hideCalculator() error {
+	ct.debuglog("hideCalculator()")
+	ct.State.calculatorVisible = false
+	ct.State.calculatorSeq++
+	ct.SetViewOnBottom(ct.Views.Calculator.Name())
+	ct.SetViewOnBottom(ct.Views.CalculatorInput.Name())
+	ct.SetActiveView(ct.Views.Table.Name())
+	ct.Update(func() error {
+		if ct.Views.Calculator.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.Calculator.Backing().Clear()
+		ct.Views.Calculator.Backing().Frame = false
+		fmt.Fprintln(ct.Views.Calculator.Backing(), "")
+
+		ct.Views.CalculatorInput.Backing().Clear()
+		return nil
+	})
+
+	return nil
+}
+
+func (ct *Cointop) toggleCalculator() error {
+	ct.debuglog("toggleCalculator()")
+	if ct.State.calculatorVisible {
+		return ct.hideCalculator()
+	}
+
+	return ct.showCalculator()
+}
+
+// calculatorToPortfolio sets the portfolio holdings of the coin of the conversion to its amount
+func (ct *Cointop) calculatorToPortfolio() error {
+	ct.debuglog("calculatorToPortfolio()")
+	result := ct.State.calculatorResult
+	if result == nil {
+		return nil
+	}
+	coin, holdings := result.calculatorPortfolioEntry()
+	if coin == nil {
+		return nil
+	}
+
+	if err := ct.hideCalculator(); err != nil {
+		return err
+	}
+	if err := ct.setPortfolioEntry(coin.Name, holdings); err != nil {
+		return err
+	}
+
+	go func() {
+		ct.UpdateTable()
+		ct.UpdateStatusbar(fmt.Sprintf("Set %s portfolio holdings to %s", coin.Symbol, ct.formatNumber(holdings)))
+	}()
+	return nil
+}
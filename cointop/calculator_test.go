@@ -0,0 +1,39 @@
+package cointop
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCalculatorInput(t *testing.T) {
+	tests := []struct {
+		input   string
+		decimal rune
+		want    calculatorQuery
+	}{
+		{"0.37 ETH in EUR", '.', calculatorQuery{0.37, "ETH", "EUR"}},
+		{"$5,000 to SOL", '.', calculatorQuery{5000, "USD", "SOL"}},
+		{"2.5 btc", '.', calculatorQuery{2.5, "btc", ""}},
+		{"2.5btc eur", '.', calculatorQuery{2.5, "btc", "eur"}},
+		{"100", '.', calculatorQuery{100, "", ""}},
+		{"1.234,5 EUR = bitcoin cash", ',', calculatorQuery{1234.5, "EUR", "bitcoin cash"}},
+		{"€20 as ETH", '.', calculatorQuery{20, "EUR", "ETH"}},
+	}
+
+	for _, test := range tests {
+		got, err := parseCalculatorInput(test.input, test.decimal)
+		if err != nil {
+			t.Errorf("parseCalculatorInput(%q) error: %v", test.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, test.want) {
+			t.Errorf("parseCalculatorInput(%q) = %+v, want %+v", test.input, *got, test.want)
+		}
+	}
+
+	for _, input := range []string{"", "ETH", "1.2.3 ETH"} {
+		if _, err := parseCalculatorInput(input, '.'); err == nil {
+			t.Errorf("parseCalculatorInput(%q) expected an error", input)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package cointop
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -66,29 +68,51 @@ func (ct *Cointop) UpdateChart() error {
 	chartLock.Lock()
 	defer chartLock.Unlock()
 
+	ct.State.chartColors = nil
+	ct.State.chartLegend = ""
+	ct.State.chartColumns = nil
+	ct.State.volumePoints = nil
+	ct.State.indicatorPoints = nil
+	ct.State.indicatorColors = nil
 	if ct.State.portfolioVisible {
 		if err := ct.PortfolioChart(); err != nil {
 			return err
 		}
+	} else if ct.State.compareChartVisible {
+		if err := ct.CompareChart(); err != nil {
+			return err
+		}
 	} else {
 		symbol := ct.selectedCoinSymbol()
 		name := ct.selectedCoinName()
 		ct.ChartPoints(symbol, name)
 	}
 
+	ct.renderChart()
+	if ct.State.chartInspectVisible {
+		ct.updateChartInspectStatusbar()
+	}
+
+	return nil
+}
+
+// renderChart renders the calculated chart points
+func (ct *Cointop) renderChart() error {
+	ct.debuglog("renderChart()")
 	var body string
 	if len(ct.State.chartPoints) == 0 {
-		body = "\n\n\n\n\nnot enough data for chart"
+		if ct.State.compareChartVisible && !ct.State.portfolioVisible && len(ct.State.compareCoins) == 0 {
+			body = ct.colorscheme.Chart("\n\n\n\n\nno coins marked for comparison")
+		} else {
+			body = ct.colorscheme.Chart("\n\n\n\n\nnot enough data for chart")
+		}
 	} else {
-		for i := range ct.State.chartPoints {
-			var s string
-			for j := range ct.State.chartPoints[i] {
-				p := ct.State.chartPoints[i][j]
-				s = fmt.Sprintf("%s%c", s, p.Ch)
-			}
-			body = fmt.Sprintf("%s%s\n", body, s)
-
+		points := ct.State.chartPoints
+		colors := ct.State.chartColors
+		if ct.State.chartInspectVisible {
+			points, colors = ct.chartCrosshair(points, colors)
 		}
+		body = ct.chartBody(points, colors, ct.State.chartLegend)
 	}
 
 	ct.Update(func() error {
@@ -97,43 +121,82 @@ func (ct *Cointop) UpdateChart() error {
 		}
 
 		ct.Views.Chart.Backing().Clear()
-		fmt.Fprint(ct.Views.Chart.Backing(), ct.colorscheme.Chart(body))
+		fmt.Fprint(ct.Views.Chart.Backing(), body)
+		ct.updateVolume()
+		ct.updateIndicator()
 		return nil
 	})
 
 	return nil
 }
 
-// ChartPoints calculates the the chart points
-func (ct *Cointop) ChartPoints(symbol string, name string) error {
-	ct.debuglog("ChartPoints()")
-	maxX := ct.ClampedWidth()
-
-	chartPointsLock.Lock()
-	defer chartPointsLock.Unlock()
+// chartBody renders the chart points. Cells tagged with a color in the chart colors are
+// drawn in that color and the legend, if set, replaces the x-axis label row
+func (ct *Cointop) chartBody(points [][]termui.Cell, colors []ISprintf, legend string) string {
+	ct.debuglog("chartBody()")
+	colorfn := func(index int) ISprintf {
+		if index >= 0 {
+			return colors[index]
+		}
+		return ct.colorscheme.Chart
+	}
 
-	// TODO: not do this (SoC)
-	go ct.updateMarketbar()
+	var body string
+	for i := range points {
+		if i == len(points)-1 && legend != "" {
+			body = fmt.Sprintf("%s%s\n", body, legend)
+			continue
+		}
 
-	chart := termui.NewLineChart()
-	chart.Height = ct.State.chartHeight
-	chart.Border = false
+		var line, run string
+		runIndex := -1
+		for j := range points[i] {
+			p := points[i][j]
+			index := -1
+			if n := int(p.Fg) - 1; p.Ch != ' ' && n >= 0 && n < len(colors) {
+				index = n
+			}
+			if index != runIndex && run != "" {
+				line = fmt.Sprintf("%s%s", line, colorfn(runIndex)(run))
+				run = ""
+			}
+			runIndex = index
+			run = fmt.Sprintf("%s%c", run, p.Ch)
+		}
+		if run != "" {
+			line = fmt.Sprintf("%s%s", line, colorfn(runIndex)(run))
+		}
+		body = fmt.Sprintf("%s%s\n", body, line)
+	}
 
-	// NOTE: empty list means don't show x-axis labels
-	chart.DataLabels = []string{""}
+	return body
+}
 
+// chartRangeBounds returns the start and end unix times of the selected chart range
+func (ct *Cointop) chartRangeBounds() (int64, int64) {
 	rangeseconds := ct.chartRangesMap[ct.State.selectedChartRange]
 	if ct.State.selectedChartRange == "YTD" {
 		ytd := time.Now().Unix() - int64(timeutil.BeginningOfYear().Unix())
 		rangeseconds = time.Duration(ytd) * time.Second
 	}
 
-	now := time.Now()
-	nowseconds := now.Unix()
-	start := nowseconds - int64(rangeseconds.Seconds())
-	end := nowseconds
+	end := time.Now().Unix()
+	start := end - int64(rangeseconds.Seconds())
+	return start, end
+}
 
-	var data []float64
+// chartGraph is the data of a chart over the selected chart range
+type chartGraph struct {
+	Times   []int64 // unix seconds
+	Prices  []float64
+	Volumes []float64
+}
+
+// chartGraphData returns the data of the coin over the selected chart range.
+// An empty symbol returns the global market cap data in billions
+func (ct *Cointop) chartGraphData(symbol string, name string) (*chartGraph, error) {
+	ct.debuglog("chartGraphData()")
+	start, end := ct.chartRangeBounds()
 
 	keyname := symbol
 	if keyname == "" {
@@ -144,45 +207,155 @@ func (ct *Cointop) ChartPoints(symbol string, name string) error {
 	cached, found := ct.cache.Get(cachekey)
 	if found {
 		// cache hit
-		data, _ = cached.([]float64)
-		ct.debuglog("soft cache hit")
+		if graph, ok := cached.(*chartGraph); ok && len(graph.Prices) > 0 {
+			ct.debuglog("soft cache hit")
+			return graph, nil
+		}
 	}
 
-	if len(data) == 0 {
-		if symbol == "" {
-			convert := ct.State.currencyConversion
-			graphData, err := ct.api.GetGlobalMarketGraphData(convert, start, end)
-			if err != nil {
-				return nil
-			}
-			for i := range graphData.MarketCapByAvailableSupply {
-				price := graphData.MarketCapByAvailableSupply[i][1]
-				data = append(data, price/1e9)
+	graph := &chartGraph{}
+	if symbol == "" {
+		convert := ct.State.currencyConversion
+		graphData, err := ct.api.GetGlobalMarketGraphData(convert, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for i := range graphData.MarketCapByAvailableSupply {
+			item := graphData.MarketCapByAvailableSupply[i]
+			graph.Times = append(graph.Times, int64(item[0]/1000))
+			graph.Prices = append(graph.Prices, item[1]/1e9)
+		}
+		for i := range graphData.VolumeUSD {
+			graph.Volumes = append(graph.Volumes, graphData.VolumeUSD[i][1])
+		}
+	} else {
+		convert := ct.State.currencyConversion
+		graphData, err := ct.api.GetCoinGraphData(convert, symbol, name, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range graphData.Price {
+			item := graphData.Price[i]
+			graph.Times = append(graph.Times, int64(item[0]/1000))
+			graph.Prices = append(graph.Prices, item[1])
+		}
+		for i := range graphData.Volume {
+			graph.Volumes = append(graph.Volumes, graphData.Volume[i][1])
+		}
+	}
+
+	ct.cache.Set(cachekey, graph, 10*time.Second)
+	go func() {
+		filecache.Set(cachekey, graph, 24*time.Hour)
+	}()
+
+	return graph, nil
+}
+
+// chartData returns the price data of the coin over the selected chart range.
+// An empty symbol returns the global market cap data in billions
+func (ct *Cointop) chartData(symbol string, name string) ([]float64, error) {
+	ct.debuglog("chartData()")
+	graph, err := ct.chartGraphData(symbol, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return graph.Prices, nil
+}
+
+// chartCandleData is the candles of a chart and the times they open at
+type chartCandleData struct {
+	Times   []int64 // unix seconds
+	Candles []termui.Candle
+}
+
+// ohlcErrorCacheTTL is how long a failed OHLC fetch is cached before it's retried
+const ohlcErrorCacheTTL = 1 * time.Minute
+
+// chartCandles returns the candles of the coin over the selected chart range. The price data
+// is bucketed into candles if the API doesn't provide OHLC data
+func (ct *Cointop) chartCandles(symbol string, name string) (*chartCandleData, error) {
+	ct.debuglog("chartCandles()")
+	if symbol != "" {
+		start, end := ct.chartRangeBounds()
+		convert := ct.State.currencyConversion
+		cachekey := ct.CacheKey(fmt.Sprintf("%s_ohlc_%s_%s", symbol, convert, strings.Replace(ct.State.selectedChartRange, " ", "", -1)))
+
+		data, found := ct.cache.Get(cachekey)
+		if !found {
+			ohlcData, err := ct.api.GetCoinOHLCData(convert, symbol, name, start, end)
+			candles := &chartCandleData{}
+			for _, item := range ohlcData.OHLC {
+				candles.Times = append(candles.Times, int64(item[0]/1000))
+				candles.Candles = append(candles.Candles, termui.Candle{Open: item[1], High: item[2], Low: item[3], Close: item[4]})
 			}
-		} else {
-			convert := ct.State.currencyConversion
-			graphData, err := ct.api.GetCoinGraphData(convert, symbol, name, start, end)
+
+			// NOTE: failed fetches are cached too so the price candles are used without retrying on every redraw
+			ttl := 10 * time.Second
 			if err != nil {
-				return nil
+				ct.debuglog(fmt.Sprintf("ohlc error: %s", err))
+				ttl = ohlcErrorCacheTTL
 			}
+			ct.cache.Set(cachekey, candles, ttl)
+			data = candles
+		} else {
+			ct.debuglog("soft cache hit")
+		}
 
-			// NOTE: edit `termui.LineChart.shortenFloatVal(float64)` to not
-			// use exponential notation.
-			for i := range graphData.Price {
-				price := graphData.Price[i][1]
-				data = append(data, price)
-			}
+		if candles, ok := data.(*chartCandleData); ok && len(candles.Candles) > 0 {
+			return candles, nil
 		}
+	}
 
-		ct.cache.Set(cachekey, data, 10*time.Second)
-		go func() {
-			filecache.Set(cachekey, data, 24*time.Hour)
-		}()
+	graph, err := ct.chartGraphData(symbol, name)
+	if err != nil {
+		return nil, err
 	}
 
-	chart.Data = data
+	return priceCandles(graph, ct.ClampedWidth()), nil
+}
+
+// priceCandles buckets the prices into at most n candles.
+// Each candle opens at the close of the previous one
+func priceCandles(graph *chartGraph, n int) *chartCandleData {
+	data := &chartCandleData{}
+	prices := graph.Prices
+	if len(prices) < 2 || n <= 0 {
+		return data
+	}
+
+	// NOTE: the first price is only the open of the first candle
+	points := prices[1:]
+	if n > len(points) {
+		n = len(points)
+	}
+
+	open := prices[0]
+	for i := 0; i < n; i++ {
+		from := i * len(points) / n
+		to := (i + 1) * len(points) / n
+		c := termui.Candle{Open: open, High: open, Low: open}
+		for _, price := range points[from:to] {
+			c.High = math.Max(c.High, price)
+			c.Low = math.Min(c.Low, price)
+			c.Close = price
+		}
+		if from < len(graph.Times) {
+			data.Times = append(data.Times, graph.Times[from])
+		}
+		data.Candles = append(data.Candles, c)
+		open = c.Close
+	}
+
+	return data
+}
+
+// chartCells lays out the chart and returns its cells by row
+func (ct *Cointop) chartCells(chart *termui.LineChart) [][]termui.Cell {
 	termui.Body = termui.NewGrid()
-	termui.Body.Width = maxX
+	termui.Body.Width = ct.ClampedWidth()
 	termui.Body.AddRows(
 		termui.NewRow(
 			termui.NewCol(12, 0, chart),
@@ -205,15 +378,144 @@ func (ct *Cointop) ChartPoints(symbol string, name string) error {
 		points = append(points, rowpoints)
 	}
 
-	ct.State.chartPoints = points
+	return points
+}
+
+// ChartPoints calculates the the chart points
+func (ct *Cointop) ChartPoints(symbol string, name string) error {
+	ct.debuglog("ChartPoints()")
+	chartPointsLock.Lock()
+	defer chartPointsLock.Unlock()
+
+	// TODO: not do this (SoC)
+	go ct.updateMarketbar()
+
+	chart := termui.NewLineChart()
+	chart.Height = ct.chartHeight()
+	chart.Border = false
+	chart.LineColor = termui.ColorDefault
+	chart.AxesColor = termui.ColorDefault
+	chart.YScale = ct.State.chartScale
+
+	var times []int64
+	var values []float64
+	var labels []string
+	var tags []termui.Attribute
+	if ct.State.candleChart {
+		data, err := ct.chartCandles(symbol, name)
+		if err != nil {
+			return nil
+		}
+
+		// NOTE: the candle colors are the index into the chart colors plus one
+		chart.Mode = "candle"
+		chart.Candles = data.Candles
+		chart.UpColor = termui.Attribute(1)
+		chart.DownColor = termui.Attribute(2)
+		ct.State.chartColors = []ISprintf{ct.colorscheme.ChartCandleUpSprintf(), ct.colorscheme.ChartCandleDownSprintf()}
+
+		times = data.Times
+		for _, c := range data.Candles {
+			values = append(values, c.Close)
+		}
+		if len(data.Candles) > 0 {
+			ct.State.chartStartValue = data.Candles[0].Open
+		}
+
+		// NOTE: the overlays are only drawn over the price line
+		if ct.hasChartOverlays() {
+			go ct.UpdateStatusbar("Overlays are not shown in candle mode")
+		}
+	} else {
+		graph, err := ct.chartGraphData(symbol, name)
+		if err != nil {
+			return nil
+		}
+
+		chart.Data = graph.Prices
+		times = graph.Times
+		values = graph.Prices
+		labels, tags = ct.applyChartOverlays(chart, values)
+		if len(values) > 0 {
+			ct.State.chartStartValue = values[0]
+		}
+	}
+
+	// NOTE: empty list means don't show x-axis labels
+	chart.DataLabels = []string{""}
+	if len(times) == len(values) {
+		chart.DataLabels = ct.chartTimeLabels(times)
+	}
+	chart.YLabelFormat = chartLabelFormat(values)
+
+	ct.State.chartPoints = ct.chartCells(chart)
+	legendCells(ct.State.chartPoints, labels, tags)
+	ct.State.chartColumns, ct.State.chartColumnOffset = chartInspectColumns(chart, times, values)
+	ct.State.volumePoints = ct.volumeCells(chart, symbol, name)
+	ct.State.indicatorPoints, ct.State.indicatorColors = ct.indicatorCells(chart, values)
 
 	return nil
 }
 
+// chartTimeLabels returns the x-axis labels of the times in a format suited to the selected chart range
+func (ct *Cointop) chartTimeLabels(times []int64) []string {
+	format := "Jan 02"
+	switch ct.State.selectedChartRange {
+	case "1H", "6H", "24H":
+		format = "15:04"
+	case "3D", "7D":
+		format = "Mon 15:04"
+	case "All Time":
+		format = "Jan 2006"
+	}
+
+	labels := make([]string, len(times))
+	for i, t := range times {
+		labels[i] = time.Unix(t, 0).Format(format)
+	}
+
+	return labels
+}
+
+// chartLabelFormat returns a y-axis label format with enough decimals to tell apart the labels
+// of the values
+func chartLabelFormat(values []float64) func(float64) string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	if math.IsInf(min, 1) {
+		return nil
+	}
+	span := max - min
+	if span == 0 {
+		span = math.Abs(max)
+	}
+
+	decimals := 2
+	if span > 0 && span < 1 {
+		decimals = int(math.Ceil(-math.Log10(span))) + 2
+	}
+	if decimals > 10 {
+		decimals = 10
+	}
+
+	return func(v float64) string {
+		return strconv.FormatFloat(v, 'f', decimals, 64)
+	}
+}
+
 // PortfolioChart renders the portfolio chart
 func (ct *Cointop) PortfolioChart() error {
 	ct.debuglog("PortfolioChart()")
-	maxX := ct.ClampedWidth()
 	chartPointsLock.Lock()
 	defer chartPointsLock.Unlock()
 
@@ -221,22 +523,14 @@ func (ct *Cointop) PortfolioChart() error {
 	go ct.updateMarketbar()
 
 	chart := termui.NewLineChart()
-	chart.Height = ct.State.chartHeight
+	chart.Height = ct.chartHeight()
 	chart.Border = false
+	chart.YScale = ct.State.chartScale
 
 	// NOTE: empty list means don't show x-axis labels
 	chart.DataLabels = []string{""}
 
-	rangeseconds := ct.chartRangesMap[ct.State.selectedChartRange]
-	if ct.State.selectedChartRange == "YTD" {
-		ytd := time.Now().Unix() - int64(timeutil.BeginningOfYear().Unix())
-		rangeseconds = time.Duration(ytd) * time.Second
-	}
-
-	now := time.Now()
-	nowseconds := now.Unix()
-	start := nowseconds - int64(rangeseconds.Seconds())
-	end := nowseconds
+	start, end := ct.chartRangeBounds()
 
 	var data []float64
 	portfolio := ct.getPortfolioSlice()
@@ -294,31 +588,7 @@ func (ct *Cointop) PortfolioChart() error {
 	}
 
 	chart.Data = data
-	termui.Body = termui.NewGrid()
-	termui.Body.Width = maxX
-	termui.Body.AddRows(
-		termui.NewRow(
-			termui.NewCol(12, 0, chart),
-		),
-	)
-
-	var points [][]termui.Cell
-	// calculate layout
-	termui.Body.Align()
-	w := termui.Body.Width
-	h := chart.Height
-	row := termui.Body.Rows[0]
-	b := row.Buffer()
-	for i := 0; i < h; i = i + 1 {
-		var rowpoints []termui.Cell
-		for j := 0; j < w; j = j + 1 {
-			p := b.At(j, i)
-			rowpoints = append(rowpoints, p)
-		}
-		points = append(points, rowpoints)
-	}
-
-	ct.State.chartPoints = points
+	ct.State.chartPoints = ct.chartCells(chart)
 
 	return nil
 }
@@ -426,6 +696,55 @@ func (ct *Cointop) ToggleCoinChart() error {
 	return nil
 }
 
+// ToggleChartMode toggles the coin chart between the line and candle modes
+func (ct *Cointop) ToggleChartMode() error {
+	ct.debuglog("ToggleChartMode()")
+	ct.State.candleChart = !ct.State.candleChart
+
+	go func() {
+		// keep these two synchronous to avoid race conditions
+		ct.ShowChartLoader()
+		ct.UpdateChart()
+	}()
+
+	return nil
+}
+
+// chartScales are the y-axis scales of the coin and portfolio charts
+var chartScales = []string{"linear", "log", "percent"}
+
+// NextChartScale switches the y-axis of the chart to the next scale
+func (ct *Cointop) NextChartScale() error {
+	ct.debuglog("NextChartScale()")
+	index := 0
+	for i, scale := range chartScales {
+		if scale == ct.State.chartScale {
+			index = i
+			break
+		}
+	}
+	ct.State.chartScale = chartScales[(index+1)%len(chartScales)]
+	ct.UpdateStatusbar(fmt.Sprintf("Chart scale: %s", ct.State.chartScale))
+
+	if err := ct.Save(); err != nil {
+		return err
+	}
+
+	go ct.UpdateChart()
+	return nil
+}
+
+// isChartScale returns true if the name is a chart scale
+func (ct *Cointop) isChartScale(name string) bool {
+	for _, scale := range chartScales {
+		if scale == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ShowChartLoader shows chart loading indicator
 func (ct *Cointop) ShowChartLoader() error {
 	ct.debuglog("ShowChartLoader()")
@@ -0,0 +1,149 @@
+package cointop
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cdyfng/coind/cointop/common/gizak/termui"
+)
+
+// chartColumn is a plotted column of the chart which can be inspected with the cursor
+type chartColumn struct {
+	Time  int64 // unix seconds
+	Value float64
+	Row   int
+}
+
+// chartInspectColumns returns the plotted columns of the chart and their x offset
+func chartInspectColumns(chart *termui.LineChart, times []int64, values []float64) ([]chartColumn, int) {
+	offset, n := chart.PlotColumns()
+	columns := make([]chartColumn, 0, n)
+	for col := 0; col < n; col++ {
+		index, value, row := chart.Column(col)
+		var t int64
+		if index < len(times) {
+			t = times[index]
+		}
+		columns = append(columns, chartColumn{t, value, row})
+	}
+
+	return columns, offset
+}
+
+// ToggleChartInspect focuses the chart and shows the inspect cursor, or hides it
+func (ct *Cointop) ToggleChartInspect() error {
+	ct.debuglog("ToggleChartInspect()")
+	if ct.State.chartInspectVisible {
+		return ct.hideChartInspect()
+	}
+
+	if ct.State.hideChart || ct.Views.Chart.Backing() == nil || len(ct.State.chartColumns) == 0 {
+		return nil
+	}
+
+	ct.State.chartInspectVisible = true
+	ct.State.chartCursor = len(ct.State.chartColumns) - 1
+	ct.SetActiveView(ct.Views.Chart.Name())
+	ct.renderChart()
+	ct.updateChartInspectStatusbar()
+	return nil
+}
+
+func (ct *Cointop) hideChartInspect() error {
+	ct.debuglog("hideChartInspect()")
+	ct.State.chartInspectVisible = false
+	ct.SetActiveView(ct.Views.Table.Name())
+	ct.renderChart()
+	ct.RowChanged()
+	return nil
+}
+
+// chartCursorFn returns a function that moves the inspect cursor by the number of columns
+func (ct *Cointop) chartCursorFn(delta int) func() error {
+	return func() error {
+		ct.debuglog("chartCursorFn()")
+		if len(ct.State.chartColumns) == 0 {
+			return nil
+		}
+
+		ct.State.chartCursor += delta
+		ct.clampChartCursor()
+		ct.renderChart()
+		ct.updateChartInspectStatusbar()
+		return nil
+	}
+}
+
+// clampChartCursor keeps the inspect cursor within the plotted columns
+func (ct *Cointop) clampChartCursor() {
+	if ct.State.chartCursor >= len(ct.State.chartColumns) {
+		ct.State.chartCursor = len(ct.State.chartColumns) - 1
+	}
+	if ct.State.chartCursor < 0 {
+		ct.State.chartCursor = 0
+	}
+}
+
+// updateChartInspectStatusbar shows the time, value and change from the start of the chart range
+// of the column under the inspect cursor
+func (ct *Cointop) updateChartInspectStatusbar() error {
+	ct.debuglog("updateChartInspectStatusbar()")
+	if !ct.State.chartInspectVisible || len(ct.State.chartColumns) == 0 {
+		return nil
+	}
+
+	column := ct.State.chartColumns[ct.State.chartCursor]
+	var change float64
+	if ct.State.chartStartValue != 0 {
+		change = (column.Value/ct.State.chartStartValue - 1) * 100
+	}
+
+	label := "Price"
+	value := ct.formatNumber(column.Value)
+	if ct.selectedCoinSymbol() == "" {
+		label = "Market Cap"
+		value = fmt.Sprintf("%sB", value)
+	}
+
+	return ct.UpdateStatusbar(fmt.Sprintf("[%s] %s: %s Change: %+.2f%%", time.Unix(column.Time, 0).Format("2006-01-02 15:04"), label, value, change))
+}
+
+// chartCrosshair draws the inspect cursor over the chart points in a new chart color
+func (ct *Cointop) chartCrosshair(points [][]termui.Cell, colors []ISprintf) ([][]termui.Cell, []ISprintf) {
+	ct.debuglog("chartCrosshair()")
+	if len(ct.State.chartColumns) == 0 {
+		return points, colors
+	}
+	ct.clampChartCursor()
+
+	// NOTE: the cursor color is the index into the chart colors plus one
+	colors = append(append([]ISprintf{}, colors...), ct.colorscheme.ChartCursorSprintf())
+	tag := termui.Attribute(len(colors))
+
+	column := ct.State.chartColumns[ct.State.chartCursor]
+	x := ct.State.chartColumnOffset + ct.State.chartCursor
+	set := func(row []termui.Cell, x int, ch rune) {
+		if x < len(row) && row[x].Ch == ' ' {
+			row[x] = termui.Cell{Ch: ch, Fg: tag, Bg: row[x].Bg}
+		}
+	}
+
+	cursor := make([][]termui.Cell, len(points))
+	// NOTE: the last two rows are the x-axis and its labels
+	for i := range points {
+		cursor[i] = append([]termui.Cell{}, points[i]...)
+		if i >= len(points)-2 {
+			continue
+		}
+		if i == column.Row {
+			set(cursor[i], x, '┼')
+			for j := range ct.State.chartColumns {
+				set(cursor[i], ct.State.chartColumnOffset+j, '─')
+			}
+			continue
+		}
+		set(cursor[i], x, '│')
+	}
+
+	return cursor, colors
+}
@@ -1,5 +1,7 @@
 package cointop
 
+import "time"
+
 // Coin is the row structure
 type Coin struct {
 	ID               string
@@ -16,13 +18,26 @@ type Coin struct {
 	PercentChange24H float64
 	PercentChange7D  float64
 	PercentChange30D float64
-	PercentChange1Y  float64	
+	PercentChange1Y  float64
 	LastUpdated      string
+	// prices of the last 7 days for the sparkline column
+	Sparkline7D []float64
+	// for the price change since the last refresh
+	Currency       string
+	PriceChange    float64
+	PriceChangedAt time.Time
+	// for the rank change since the last refresh
+	PreviousRank  int
+	RankChangedAt time.Time
+	// for secondary currency conversion
+	SecondaryPrice     float64
+	SecondaryMarketCap float64
 	// for favorites
 	Favorite bool
 	// for portfolio
-	Holdings float64
-	Balance  float64
+	Holdings         float64
+	Balance          float64
+	SecondaryBalance float64
 }
 
 // AllCoins returns a slice of all the coins
@@ -32,7 +47,7 @@ func (ct *Cointop) AllCoins() []*Coin {
 		var list []*Coin
 		for i := range ct.State.allCoins {
 			coin := ct.State.allCoins[i]
-			if coin.Favorite {
+			if ct.inWatchlist(ct.State.selectedWatchlist, coin) {
 				list = append(list, coin)
 			}
 		}
@@ -12,11 +12,12 @@ import (
 	"github.com/cdyfng/coind/cointop/common/api"
 	"github.com/cdyfng/coind/cointop/common/api/types"
 	"github.com/cdyfng/coind/cointop/common/filecache"
+	"github.com/cdyfng/coind/cointop/common/filter"
 	"github.com/cdyfng/coind/cointop/common/gizak/termui"
-	"github.com/cdyfng/coind/cointop/common/humanize"
 	"github.com/cdyfng/coind/cointop/common/table"
 	"github.com/miguelmota/gocui"
 	"github.com/patrickmn/go-cache"
+	"golang.org/x/text/language"
 )
 
 // TODO: clean up and optimize codebase
@@ -27,6 +28,8 @@ var ErrInvalidAPIChoice = errors.New("Invalid API choice")
 // Views are all views in cointop
 type Views struct {
 	Chart               *ChartView
+	Volume              *VolumeView
+	Indicator           *IndicatorView
 	Table               *TableView
 	TableHeader         *TableHeaderView
 	Marketbar           *MarketbarView
@@ -36,6 +39,15 @@ type Views struct {
 	ConvertMenu         *ConvertMenuView
 	Input               *InputView
 	PortfolioUpdateMenu *PortfolioUpdateMenuView
+	TableColumnsMenu    *TableColumnsMenuView
+	WatchlistsMenu      *WatchlistsMenuView
+	ColorschemeMenu     *ColorschemeMenuView
+	TopMovers           *TopMoversView
+	Calculator          *CalculatorView
+	CalculatorInput     *CalculatorInputView
+	FilterField         *FilterFieldView
+	CommandField        *CommandFieldView
+	SearchResults       *SearchResultsView
 }
 
 // State is the state preferences of cointop
@@ -48,30 +60,128 @@ type State struct {
 	convertMenuVisible bool
 	defaultView        string
 
+	// locale of the number separators and the currency symbol placement, undefined for the US format
+	locale language.Tag
+
+	// secondary currency is shown alongside the primary currency conversion
+	secondaryCurrencyConversion string
+	secondaryConversionRate     float64
+	convertMenuSecondary        bool
+
 	// DEPRECATED: favorites by 'symbol' is deprecated because of collisions.
 	favoritesBySymbol map[string]bool
 
 	favorites                  map[string]bool
+	watchlists                 map[string]map[string]bool
+	selectedWatchlist          string
+	watchlistsMenuVisible      bool
+	watchlistsMenuIndex        int
+	watchlistsMenuCoin         *Coin
 	filterByFavorites          bool
 	helpVisible                bool
+	helpFilter                 string
+	helpOffset                 int
 	hideMarketbar              bool
 	hideChart                  bool
 	hideStatusbar              bool
 	lastSelectedRowIndex       int
+	tableCursorY               int
+	lastRefresh                time.Time
 	page                       int
 	perPage                    int
 	portfolio                  *Portfolio
 	portfolioVisible           bool
 	portfolioUpdateMenuVisible bool
+	calculatorVisible          bool
+	calculatorSeq              int
+	calculatorResult           *calculatorResult
 	refreshRate                time.Duration
+	restoredCoin               string
 	searchFieldVisible         bool
 	selectedCoin               *Coin
 	selectedChartRange         string
 	shortcutKeys               map[string]string
+	scopedShortcutKeys         map[string]map[string]string
 	sortDesc                   bool
 	sortBy                     string
 	onlyTable                  bool
 	chartHeight                int
+	screenLayout               screenLayout
+	candleChart                bool
+	chartScale                 string
+	volumeHeight               int
+	volumePoints               [][]termui.Cell
+
+	// inspect cursor over the plotted columns of the chart
+	chartInspectVisible bool
+	chartCursor         int
+	chartColumns        []chartColumn
+	chartColumnOffset   int
+	chartStartValue     float64
+
+	// colorscheme menu previews and the modification time of the colorscheme file when it was loaded
+	colorschemeMenuVisible  bool
+	colorschemeMenuIndex    int
+	colorschemeMenuNames    []string
+	colorschemeMenuOriginal string
+	colorschemeModTime      time.Time
+
+	// key sequence dispatchers by view, the conflicts between the bound keys and the invalid
+	// shortcuts of the config
+	keySequences     map[string]*keySequenceDispatcher
+	fixedKeys        []viewKeyPress
+	keyConflicts     []string
+	shortcutWarnings []string
+
+	// table columns, widths and number formats by table view, the priority of the columns when they
+	// don't fit and the decimals of the compact number format
+	tableColumns        map[string][]string
+	tableColumnWidths   map[string]map[string]int
+	tableColumnFormats  map[string]map[string]string
+	tableColumnPriority []string
+	compactPrecision    int
+	columnsMenu         *tableColumnsMenuState
+	columnsMenuVisible  bool
+
+	// filter expression which restricts the coins table
+	filter             *filter.Expr
+	filterInput        string
+	filterFieldVisible bool
+	filters            map[string]string
+
+	// command palette input and the commands of this and previous sessions
+	commandFieldVisible bool
+	commandHistory      []string
+	commandHistoryIndex int
+
+	// search results dropdown and the matches cycled through in the table
+	searchResults     []*Coin
+	searchResultIndex int
+	searchMatches     []*Coin
+	searchMatchIndex  int
+	searchMatchTable  string
+
+	// coins marked for the compare chart and the colors and legend of the rendered chart series
+	compareCoins        []string
+	compareChartVisible bool
+	chartColors         []ISprintf
+	chartLegend         string
+
+	// indicators drawn over the price chart and in the indicator panel
+	chartOverlays     map[string]bool
+	indicatorPanel    string
+	indicatorSettings indicatorSettings
+	indicatorPoints   [][]termui.Cell
+	indicatorColors   []ISprintf
+
+	// top movers panel, the period its coins are ranked by, the liquidity filters and the ranks of the
+	// previous days for the rank changes since yesterday
+	topMoversVisible      bool
+	topMoversPeriod       string
+	topMoversDaily        bool
+	topMoversMinMarketCap float64
+	topMoversMinVolume    float64
+	rankHistory           map[string]map[string]int
 }
 
 // Cointop cointop
@@ -130,6 +240,7 @@ type Config struct {
 	HideStatusbar       bool
 	OnlyTable           bool
 	RefreshRate         *uint
+	Fresh               bool
 }
 
 // APIKeys is api keys structure
@@ -155,23 +266,26 @@ func NewCointop(config *Config) (*Cointop, error) {
 	}
 
 	ct := &Cointop{
-		apiChoice:      CoinGecko,
-		apiKeys:        new(APIKeys),
-		forceRefresh:   make(chan bool),
-		maxTableWidth:  200,
-		ActionsMap:     ActionsMap(),
-		cache:          cache.New(1*time.Minute, 2*time.Minute),
-		configFilepath: configFilepath,
-		chartRanges:    chartRanges(),
-		debug:          debug,
-		chartRangesMap: chartRangesMap(),
-		limiter:        time.Tick(2 * time.Second),
+		apiChoice:        CoinGecko,
+		apiKeys:          new(APIKeys),
+		forceRefresh:     make(chan bool),
+		maxTableWidth:    200,
+		ActionsMap:       ActionsMap(),
+		cache:            cache.New(1*time.Minute, 2*time.Minute),
+		configFilepath:   configFilepath,
+		chartRanges:      chartRanges(),
+		debug:            debug,
+		chartRangesMap:   chartRangesMap(),
+		limiter:          time.Tick(2 * time.Second),
+		TableColumnOrder: TableColumnOrder(),
 		State: &State{
 			allCoins:           []*Coin{},
 			currencyConversion: "USD",
 			// DEPRECATED: favorites by 'symbol' is deprecated because of collisions. Kept for backward compatibility.
 			favoritesBySymbol:  make(map[string]bool),
 			favorites:          make(map[string]bool),
+			watchlists:         make(map[string]map[string]bool),
+			selectedWatchlist:  defaultWatchlist,
 			hideMarketbar:      config.HideMarketbar,
 			hideChart:          config.HideChart,
 			hideStatusbar:      config.HideStatusbar,
@@ -179,17 +293,28 @@ func NewCointop(config *Config) (*Cointop, error) {
 			refreshRate:        60 * time.Second,
 			selectedChartRange: "7D",
 			shortcutKeys:       DefaultShortcuts(),
+			scopedShortcutKeys: make(map[string]map[string]string),
 			sortBy:             "rank",
 			page:               0,
 			perPage:            100,
 			portfolio: &Portfolio{
 				Entries: make(map[string]*PortfolioEntry, 0),
 			},
-			chartHeight: 10,
+			chartHeight:        10,
+			chartScale:         "linear",
+			tableColumns:       make(map[string][]string),
+			tableColumnWidths:  make(map[string]map[string]int),
+			tableColumnFormats: make(map[string]map[string]string),
+			compactPrecision:   defaultCompactPrecision,
+			filters:            make(map[string]string),
+			chartOverlays:      make(map[string]bool),
+			indicatorSettings:  defaultIndicatorSettings(),
+			topMoversPeriod:    "24h",
 		},
-		TableColumnOrder: TableColumnOrder(),
 		Views: &Views{
 			Chart:               NewChartView(),
+			Volume:              NewVolumeView(),
+			Indicator:           NewIndicatorView(),
 			Table:               NewTableView(),
 			TableHeader:         NewTableHeaderView(),
 			Marketbar:           NewMarketbarView(),
@@ -199,6 +324,15 @@ func NewCointop(config *Config) (*Cointop, error) {
 			ConvertMenu:         NewConvertMenuView(),
 			Input:               NewInputView(),
 			PortfolioUpdateMenu: NewPortfolioUpdateMenuView(),
+			TableColumnsMenu:    NewTableColumnsMenuView(),
+			WatchlistsMenu:      NewWatchlistsMenuView(),
+			ColorschemeMenu:     NewColorschemeMenuView(),
+			TopMovers:           NewTopMoversView(),
+			Calculator:          NewCalculatorView(),
+			CalculatorInput:     NewCalculatorInputView(),
+			FilterField:         NewFilterFieldView(),
+			CommandField:        NewCommandFieldView(),
+			SearchResults:       NewSearchResultsView(),
 		},
 	}
 
@@ -207,6 +341,10 @@ func NewCointop(config *Config) (*Cointop, error) {
 		return nil, err
 	}
 
+	if err := ct.loadCommandHistory(); err != nil {
+		return nil, err
+	}
+
 	ct.cache.Set("onlyTable", ct.State.onlyTable, cache.NoExpiration)
 	ct.cache.Set("hideMarketbar", ct.State.hideMarketbar, cache.NoExpiration)
 	ct.cache.Set("hideChart", ct.State.hideChart, cache.NoExpiration)
@@ -295,6 +433,15 @@ func NewCointop(config *Config) (*Cointop, error) {
 		return true
 	})
 
+	ct.loadRankHistory()
+
+	// NOTE: the session of the previous launch is restored once the cached coins are loaded
+	if !config.Fresh {
+		if err := ct.loadSession(); err != nil {
+			ct.debuglog(fmt.Sprintf("session error: %s", err))
+		}
+	}
+
 	if len(ct.State.allCoins) > 1 {
 		max := len(ct.State.allCoins)
 		if max > 100 {
@@ -319,10 +466,11 @@ func NewCointop(config *Config) (*Cointop, error) {
 		return true
 	})
 
-	var globaldata []float64
+	var globaldata chartGraph
 	chartcachekey := ct.CacheKey(fmt.Sprintf("%s_%s", "globaldata", strings.Replace(ct.State.selectedChartRange, " ", "", -1)))
-	filecache.Get(chartcachekey, &globaldata)
-	ct.cache.Set(chartcachekey, globaldata, 10*time.Second)
+	if err := filecache.Get(chartcachekey, &globaldata); err == nil && len(globaldata.Prices) > 0 {
+		ct.cache.Set(chartcachekey, &globaldata, 10*time.Second)
+	}
 
 	var market types.GlobalMarketData
 	marketcachekey := ct.CacheKey("market")
@@ -367,9 +515,11 @@ func (ct *Cointop) Run() error {
 
 // PriceConfig is the config options for the price command
 type PriceConfig struct {
-	Coin      string
-	Currency  string
-	APIChoice string
+	Coin           string
+	Currency       string
+	APIChoice      string
+	Locale         string
+	ConfigFilepath string
 }
 
 // PrintPrice outputs the current price of the coin
@@ -388,8 +538,89 @@ func PrintPrice(config *PriceConfig) error {
 		return err
 	}
 
+	configFilepath := defaultConfigPath
+	if config.ConfigFilepath != "" {
+		configFilepath = config.ConfigFilepath
+	}
+
+	ct := &Cointop{
+		configFilepath: configFilepath,
+		State:          &State{},
+	}
+
+	// NOTE: the locale flag overrides the locale of the config file
+	if config.Locale != "" {
+		tag, err := parseLocale(config.Locale)
+		if err != nil {
+			return err
+		}
+		ct.State.locale = tag
+	} else if _, err := os.Stat(ct.configPath()); err == nil {
+		if err := ct.parseConfig(); err != nil {
+			return err
+		}
+		if err := ct.loadLocaleFromConfig(); err != nil {
+			return err
+		}
+	}
+
 	symbol := currencySymbol(config.Currency)
-	fmt.Fprintf(os.Stdout, "%s%s", symbol, humanize.Commaf(price))
+	fmt.Fprintf(os.Stdout, "%s", ct.formatCurrency(symbol, ct.formatNumber(price)))
+
+	return nil
+}
+
+// KeysConfig is the config options for the keys command
+type KeysConfig struct {
+	ConfigFilepath string
+	Filter         string
+}
+
+// PrintKeys outputs the actions grouped by category with their shortcut keys and descriptions
+func PrintKeys(config *KeysConfig) error {
+	configFilepath := defaultConfigPath
+	if config.ConfigFilepath != "" {
+		configFilepath = config.ConfigFilepath
+	}
+
+	ct := &Cointop{
+		ActionsMap:     ActionsMap(),
+		configFilepath: configFilepath,
+		State: &State{
+			shortcutKeys:       DefaultShortcuts(),
+			scopedShortcutKeys: make(map[string]map[string]string),
+		},
+	}
+
+	// NOTE: the shortcuts of the config file override the defaults, same as when running cointop
+	if _, err := os.Stat(ct.configPath()); err == nil {
+		if err := ct.parseConfig(); err != nil {
+			return err
+		}
+		if err := ct.loadShortcutsFromConfig(); err != nil {
+			return err
+		}
+	}
+
+	entries := ct.helpEntries(config.Filter)
+	lines := helpLines(entries, func(category string) string {
+		return category
+	}, func(e helpEntry) string {
+		return fmt.Sprintf("  %-18s %-34s %s", e.helpKeys(), e.Action, e.Description)
+	})
+	if len(lines) == 0 {
+		lines = []string{"No matching actions"}
+	}
+	_, conflicts := ct.keySequenceTries(ct.shortcutBindings())
+	if conflicts = append(ct.State.shortcutWarnings, conflicts...); len(conflicts) > 0 {
+		lines = append(lines, "", "Key conflicts")
+		for _, conflict := range conflicts {
+			lines = append(lines, fmt.Sprintf("  %s", conflict))
+		}
+	}
+	for _, line := range lines {
+		fmt.Fprintln(os.Stdout, line)
+	}
 
 	return nil
 }
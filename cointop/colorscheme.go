@@ -1,15 +1,16 @@
 package cointop
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 
 	fcolor "github.com/fatih/color"
 	gocui "github.com/miguelmota/gocui"
 	xtermcolor "github.com/tomnomnom/xtermcolor"
 )
 
-// TODO: fix hex color support
-
 // colorschemeColors is a map of color string names to Attribute types
 type colorschemeColors map[string]interface{}
 
@@ -23,6 +24,8 @@ type colorCache map[string]ISprintf
 type Colorscheme struct {
 	colors colorschemeColors
 	cache  colorCache
+	// NOTE: the colors are replaced when the colorscheme is changed or its file is edited
+	mu sync.RWMutex
 }
 
 var fgcolorschemeColorsMap = map[string]fcolor.Attribute{
@@ -81,6 +84,45 @@ func (c *Colorscheme) Chart(a ...interface{}) string {
 	return c.color("chart", a...)
 }
 
+// chartSeriesColors are the fallback colors of the compare chart series
+var chartSeriesColors = []string{"cyan", "yellow", "magenta", "green", "red", "blue"}
+
+// ChartVolume ...
+func (c *Colorscheme) ChartVolume(a ...interface{}) string {
+	return c.toSprintfOrFg("chart_volume", "cyan")(a...)
+}
+
+// ChartCursorSprintf returns the sprintf of the chart inspect cursor
+func (c *Colorscheme) ChartCursorSprintf() ISprintf {
+	return c.toSprintfOrFg("chart_cursor", "yellow")
+}
+
+// ChartSeriesSprintf returns the sprintf of the nth compare chart series
+func (c *Colorscheme) ChartSeriesSprintf(i int) ISprintf {
+	i = i % len(chartSeriesColors)
+	return c.toSprintfOrFg(fmt.Sprintf("chart_series_%d", i+1), chartSeriesColors[i])
+}
+
+// ChartCandleUpSprintf returns the sprintf of the candles closing higher than they opened
+func (c *Colorscheme) ChartCandleUpSprintf() ISprintf {
+	return c.toSprintfOrFg("chart_candle_up", "green")
+}
+
+// TableRowFlashUp returns the text in the color of a price which went up since the last refresh
+func (c *Colorscheme) TableRowFlashUp(a ...interface{}) string {
+	return c.toSprintfOrFgBg("table_row_flash_up", "black", "green")(a...)
+}
+
+// TableRowFlashDown returns the text in the color of a price which went down since the last refresh
+func (c *Colorscheme) TableRowFlashDown(a ...interface{}) string {
+	return c.toSprintfOrFgBg("table_row_flash_down", "black", "red")(a...)
+}
+
+// ChartCandleDownSprintf returns the sprintf of the candles closing lower than they opened
+func (c *Colorscheme) ChartCandleDownSprintf() ISprintf {
+	return c.toSprintfOrFg("chart_candle_down", "red")
+}
+
 // Marketbar ...
 func (c *Colorscheme) Marketbar(a ...interface{}) string {
 	return c.color("marketbar", a...)
@@ -241,20 +283,43 @@ func (c *Colorscheme) SetViewActiveColor(view *gocui.View, name string) {
 	view.SelBgColor = c.gocuiBgColor(name)
 }
 
+// SetColors replaces the colors of the colorscheme and clears the cached sprintfs
+func (c *Colorscheme) SetColors(colors colorschemeColors) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.colors = colors
+	c.cache = make(colorCache)
+}
+
+// value returns the value of the color key
+func (c *Colorscheme) value(key string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.colors[key]
+	return v, ok
+}
+
 func (c *Colorscheme) toSprintf(name string) ISprintf {
-	if cached, ok := c.cache[name]; ok {
+	c.mu.RLock()
+	cached, ok := c.cache[name]
+	c.mu.RUnlock()
+	if ok {
 		return cached
 	}
 
+	// NOTE: hold the lock while reading the colors so a sprintf of replaced colors isn't cached
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	var attrs []fcolor.Attribute
-	if v, ok := c.colors[name+"_fg"].(string); ok {
+	if v, ok := c.colors[name+"_fg"]; ok {
 		if fg, ok := c.toFgAttr(v); ok {
-			attrs = append(attrs, fg)
+			attrs = append(attrs, fg...)
 		}
 	}
-	if v, ok := c.colors[name+"_bg"].(string); ok {
+	if v, ok := c.colors[name+"_bg"]; ok {
 		if bg, ok := c.toBgAttr(v); ok {
-			attrs = append(attrs, bg)
+			attrs = append(attrs, bg...)
 		}
 	}
 	if v, ok := c.colors[name+"_bold"].(bool); ok {
@@ -272,12 +337,28 @@ func (c *Colorscheme) toSprintf(name string) ISprintf {
 	return c.cache[name]
 }
 
+// toSprintfOrFg returns the sprintf of the color name or of the fallback foreground color
+// for colorschemes that don't set it
+func (c *Colorscheme) toSprintfOrFg(name string, fg string) ISprintf {
+	if _, ok := c.value(name + "_fg"); !ok {
+		return fcolor.New(fgcolorschemeColorsMap[fg]).SprintFunc()
+	}
+	return c.toSprintf(name)
+}
+
+func (c *Colorscheme) toSprintfOrFgBg(name string, fg string, bg string) ISprintf {
+	if _, ok := c.value(name + "_fg"); !ok {
+		return fcolor.New(fgcolorschemeColorsMap[fg], bgcolorschemeColorsMap[bg]).SprintFunc()
+	}
+	return c.toSprintf(name)
+}
+
 func (c *Colorscheme) color(name string, a ...interface{}) string {
 	return c.toSprintf(name)(a...)
 }
 
 func (c *Colorscheme) gocuiFgColor(name string) gocui.Attribute {
-	if v, ok := c.colors[name+"_fg"].(string); ok {
+	if v, ok := c.value(name + "_fg"); ok {
 		if fg, ok := c.toGocuiAttr(v); ok {
 			return fg
 		}
@@ -287,7 +368,7 @@ func (c *Colorscheme) gocuiFgColor(name string) gocui.Attribute {
 }
 
 func (c *Colorscheme) gocuiBgColor(name string) gocui.Attribute {
-	if v, ok := c.colors[name+"_bg"].(string); ok {
+	if v, ok := c.value(name + "_bg"); ok {
 		if bg, ok := c.toGocuiAttr(v); ok {
 			return bg
 		}
@@ -296,28 +377,34 @@ func (c *Colorscheme) gocuiBgColor(name string) gocui.Attribute {
 	return gocui.ColorDefault
 }
 
-func (c *Colorscheme) toFgAttr(v string) (fcolor.Attribute, bool) {
-	if attr, ok := fgcolorschemeColorsMap[v]; ok {
-		return attr, true
+// toFgAttr converts a color name, 256 color index or hex color to the foreground Attribute types
+func (c *Colorscheme) toFgAttr(v interface{}) ([]fcolor.Attribute, bool) {
+	if name, ok := v.(string); ok {
+		if attr, ok := fgcolorschemeColorsMap[name]; ok {
+			return []fcolor.Attribute{attr}, true
+		}
 	}
 
-	if code, ok := hexToAnsi(v); ok {
-		return fcolor.Attribute(code), true
+	if code, ok := colorIndex(v); ok {
+		return []fcolor.Attribute{38, 5, fcolor.Attribute(code)}, true
 	}
 
-	return 0, false
+	return nil, false
 }
 
-func (c *Colorscheme) toBgAttr(v string) (fcolor.Attribute, bool) {
-	if attr, ok := bgcolorschemeColorsMap[v]; ok {
-		return attr, true
+// toBgAttr converts a color name, 256 color index or hex color to the background Attribute types
+func (c *Colorscheme) toBgAttr(v interface{}) ([]fcolor.Attribute, bool) {
+	if name, ok := v.(string); ok {
+		if attr, ok := bgcolorschemeColorsMap[name]; ok {
+			return []fcolor.Attribute{attr}, true
+		}
 	}
 
-	if code, ok := hexToAnsi(v); ok {
-		return fcolor.Attribute(code), true
+	if code, ok := colorIndex(v); ok {
+		return []fcolor.Attribute{48, 5, fcolor.Attribute(code)}, true
 	}
 
-	return 0, false
+	return nil, false
 }
 
 // toBoldAttr converts a boolean to an Attribute type
@@ -330,38 +417,51 @@ func (c *Colorscheme) toUnderlineAttr(v bool) (fcolor.Attribute, bool) {
 	return fcolor.Underline, v
 }
 
-// toGocuiAttr converts a color string name to a gocui Attribute type
-func (c *Colorscheme) toGocuiAttr(v string) (gocui.Attribute, bool) {
-	if attr, ok := gocuiColorschemeColorsMap[v]; ok {
-		return attr, true
+// toGocuiAttr converts a color name, 256 color index or hex color to a gocui Attribute type
+func (c *Colorscheme) toGocuiAttr(v interface{}) (gocui.Attribute, bool) {
+	if name, ok := v.(string); ok {
+		if attr, ok := gocuiColorschemeColorsMap[name]; ok {
+			return attr, true
+		}
 	}
 
-	if code, ok := hexToAnsi(v); ok {
-		return gocui.Attribute(code), true
+	// NOTE: in 256 color output mode the gocui colors are the color indexes plus one,
+	// with zero being the default color
+	if code, ok := colorIndex(v); ok {
+		return gocui.Attribute(code) + 1, true
 	}
 
 	return 0, false
 }
 
-// hexToAnsi converts a hex color string to a uint8 ansi code
-func hexToAnsi(h string) (uint8, bool) {
-	if h == "" {
-		return 0, false
-	}
-
-	n, err := strconv.Atoi(h)
-	if err == nil {
-		if n <= 255 {
-			return uint8(n), true
+// colorIndex converts a 256 color index, as a number or a string, or a "#rrggbb" hex color to
+// the 256 color index. Hex colors are mapped to the closest xterm color
+func colorIndex(v interface{}) (uint8, bool) {
+	switch t := v.(type) {
+	case int64:
+		if t >= 0 && t <= 255 {
+			return uint8(t), true
+		}
+	case string:
+		if n, err := strconv.Atoi(t); err == nil {
+			if n >= 0 && n <= 255 {
+				return uint8(n), true
+			}
+			return 0, false
 		}
-	}
 
-	code, err := xtermcolor.FromHexStr(h)
-	if err != nil {
-		return 0, false
+		// NOTE: the hash is optional for backward compatibility
+		if len(strings.TrimPrefix(t, "#")) != 6 {
+			return 0, false
+		}
+		code, err := xtermcolor.FromHexStr(t)
+		if err != nil {
+			return 0, false
+		}
+		return code, true
 	}
 
-	return code, true
+	return 0, false
 }
 
 // gocui can use xterm colors
@@ -0,0 +1,277 @@
+package cointop
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cdyfng/coind/cointop/common/color"
+	"github.com/cdyfng/coind/cointop/common/pad"
+)
+
+// colorschemesDir is the directory of the installed colorschemes
+var colorschemesDir = "~/.cointop/colors"
+
+// colorschemeWatchInterval is how often the file of the colorscheme is checked for changes
+var colorschemeWatchInterval = 1 * time.Second
+
+// ColorschemeMenuView is structure for colorscheme menu view
+type ColorschemeMenuView struct {
+	*View
+}
+
+// NewColorschemeMenuView returns a new colorscheme menu view
+func NewColorschemeMenuView() *ColorschemeMenuView {
+	return &ColorschemeMenuView{NewView("colorschememenu")}
+}
+
+// colorschemePath returns the path of the colorscheme file
+func colorschemePath(name string) string {
+	return NormalizePath(fmt.Sprintf("%s/%s.toml", colorschemesDir, name))
+}
+
+// installedColorschemes returns the names of the colorschemes in the colors directory and the
+// default colorscheme, sorted by name
+func installedColorschemes() []string {
+	names := []string{defaultColorscheme}
+	files, _ := ioutil.ReadDir(NormalizePath(colorschemesDir))
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), ".toml")
+		if f.IsDir() || filepath.Ext(f.Name()) != ".toml" || name == defaultColorscheme {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// setColorscheme loads the colors of the colorscheme and redraws the views with them
+func (ct *Cointop) setColorscheme(name string) error {
+	ct.debuglog("setColorscheme()")
+	colors, err := ct.colorschemeColors(name)
+	if err != nil {
+		return err
+	}
+
+	ct.colorschemeName = name
+	ct.State.colorschemeModTime = colorschemeModTime(name)
+	ct.colorscheme.SetColors(colors)
+	ct.redrawColors()
+	return nil
+}
+
+// colorschemeModTime returns the modification time of the colorscheme file, or zero if there's no file
+func colorschemeModTime(name string) time.Time {
+	info, err := os.Stat(colorschemePath(name))
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// redrawColors sets the colors of the views and redraws their content with the current colorscheme
+func (ct *Cointop) redrawColors() {
+	ct.debuglog("redrawColors()")
+	ct.Update(func() error {
+		ct.g.FgColor = ct.colorscheme.BaseFg()
+		ct.g.BgColor = ct.colorscheme.BaseBg()
+		views := map[*View]string{
+			ct.Views.Marketbar.View:           "marketbar",
+			ct.Views.Chart.View:               "chart",
+			ct.Views.Volume.View:              "chart",
+			ct.Views.Indicator.View:           "chart",
+			ct.Views.TableHeader.View:         "table_header",
+			ct.Views.Statusbar.View:           "statusbar",
+			ct.Views.SearchField.View:         "searchbar",
+			ct.Views.SearchResults.View:       "menu",
+			ct.Views.FilterField.View:         "searchbar",
+			ct.Views.CommandField.View:        "searchbar",
+			ct.Views.Help.View:                "menu",
+			ct.Views.PortfolioUpdateMenu.View: "menu",
+			ct.Views.Input.View:               "menu",
+			ct.Views.ConvertMenu.View:         "menu",
+			ct.Views.WatchlistsMenu.View:      "menu",
+			ct.Views.TableColumnsMenu.View:    "menu",
+			ct.Views.ColorschemeMenu.View:     "menu",
+			ct.Views.TopMovers.View:           "menu",
+			ct.Views.Calculator.View:          "menu",
+			ct.Views.CalculatorInput.View:     "menu",
+		}
+		for view, name := range views {
+			if view.Backing() != nil {
+				ct.colorscheme.SetViewColor(view.Backing(), name)
+			}
+		}
+		if ct.Views.Table.Backing() != nil {
+			ct.colorscheme.SetViewActiveColor(ct.Views.Table.Backing(), "table_row_active")
+		}
+		return nil
+	})
+
+	go func() {
+		ct.updateMarketbar()
+		ct.UpdateTable()
+		ct.UpdateChart()
+		ct.UpdateStatusbar("")
+		if ct.State.colorschemeMenuVisible {
+			ct.updateColorschemeMenu()
+		}
+	}()
+}
+
+// watchColorscheme reloads the colorscheme when its file is edited
+func (ct *Cointop) watchColorscheme() {
+	ct.debuglog("watchColorscheme()")
+	ct.State.colorschemeModTime = colorschemeModTime(ct.colorschemeName)
+	go func() {
+		for range time.Tick(colorschemeWatchInterval) {
+			ct.Update(ct.reloadColorschemeIfChanged)
+		}
+	}()
+}
+
+// reloadColorschemeIfChanged reloads the colorscheme if its file changed since it was loaded
+func (ct *Cointop) reloadColorschemeIfChanged() error {
+	modTime := colorschemeModTime(ct.colorschemeName)
+	if modTime.IsZero() || modTime.Equal(ct.State.colorschemeModTime) {
+		return nil
+	}
+
+	ct.debuglog("reloadColorschemeIfChanged()")
+	ct.State.colorschemeModTime = modTime
+	if err := ct.setColorscheme(ct.colorschemeName); err != nil {
+		// NOTE: keep the current colors while the file is invalid, e.g. while it's being edited
+		go ct.UpdateStatusbar(fmt.Sprintf("Colorscheme error: %s", err))
+	}
+
+	return nil
+}
+
+func (ct *Cointop) updateColorschemeMenu() {
+	ct.debuglog("updateColorschemeMenu()")
+	title := "Colorscheme"
+	header := ct.colorscheme.MenuHeader(fmt.Sprintf(" %s %s\n\n", title, pad.Left("[q] close menu ", ct.ClampedWidth()-len(title)-1, " ")))
+	helpline := fmt.Sprintf(" [↑ ↓] preview [enter] select\n Colorschemes are installed in %s\n\n", colorschemesDir)
+
+	var body string
+	for i, name := range ct.State.colorschemeMenuNames {
+		active := " "
+		if name == ct.State.colorschemeMenuOriginal {
+			active = "*"
+		}
+		label := ct.colorscheme.MenuLabel(name)
+		cursor := " "
+		if i == ct.State.colorschemeMenuIndex {
+			cursor = ct.colorscheme.MenuLabelActive(color.Bold(">"))
+			label = ct.colorscheme.MenuLabelActive(color.Bold(name))
+		}
+		body = fmt.Sprintf("%s %s %s %s\n", body, cursor, active, label)
+	}
+
+	content := fmt.Sprintf("%s%s%s", header, helpline, body)
+	ct.Update(func() error {
+		if ct.Views.ColorschemeMenu.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.ColorschemeMenu.Backing().Clear()
+		ct.Views.ColorschemeMenu.Backing().Frame = true
+		fmt.Fprintln(ct.Views.ColorschemeMenu.Backing(), content)
+		return nil
+	})
+}
+
+func (ct *Cointop) showColorschemeMenu() error {
+	ct.debuglog("showColorschemeMenu()")
+	ct.State.colorschemeMenuNames = installedColorschemes()
+	ct.State.colorschemeMenuOriginal = ct.colorschemeName
+	ct.State.colorschemeMenuIndex = 0
+	for i, name := range ct.State.colorschemeMenuNames {
+		if name == ct.colorschemeName {
+			ct.State.colorschemeMenuIndex = i
+		}
+	}
+	ct.State.colorschemeMenuVisible = true
+	ct.updateColorschemeMenu()
+	ct.SetActiveView(ct.Views.ColorschemeMenu.Name())
+	return nil
+}
+
+func (ct *Cointop) hideColorschemeMenu() error {
+	ct.debuglog("hideColorschemeMenu()")
+	ct.State.colorschemeMenuVisible = false
+	ct.SetViewOnBottom(ct.Views.ColorschemeMenu.Name())
+	ct.SetActiveView(ct.Views.Table.Name())
+	ct.Update(func() error {
+		if ct.Views.ColorschemeMenu.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.ColorschemeMenu.Backing().Clear()
+		ct.Views.ColorschemeMenu.Backing().Frame = false
+		fmt.Fprintln(ct.Views.ColorschemeMenu.Backing(), "")
+		return nil
+	})
+	return nil
+}
+
+func (ct *Cointop) toggleColorschemeMenu() error {
+	ct.debuglog("toggleColorschemeMenu()")
+	if ct.State.colorschemeMenuVisible {
+		return ct.colorschemeMenuCancel()
+	}
+	return ct.showColorschemeMenu()
+}
+
+// colorschemeMenuCursorFn returns a function which moves the menu selection by the given offset
+// and previews the selected colorscheme
+func (ct *Cointop) colorschemeMenuCursorFn(offset int) func() error {
+	return func() error {
+		ct.debuglog("colorschemeMenuCursor()")
+		n := len(ct.State.colorschemeMenuNames)
+		ct.State.colorschemeMenuIndex += offset
+		if ct.State.colorschemeMenuIndex >= n {
+			ct.State.colorschemeMenuIndex = n - 1
+		}
+		if ct.State.colorschemeMenuIndex < 0 {
+			ct.State.colorschemeMenuIndex = 0
+		}
+		if err := ct.setColorscheme(ct.State.colorschemeMenuNames[ct.State.colorschemeMenuIndex]); err != nil {
+			go ct.UpdateStatusbar(fmt.Sprintf("Colorscheme error: %s", err))
+		}
+		ct.updateColorschemeMenu()
+		return nil
+	}
+}
+
+// colorschemeMenuSelect keeps the previewed colorscheme and saves it to the config
+func (ct *Cointop) colorschemeMenuSelect() error {
+	ct.debuglog("colorschemeMenuSelect()")
+	if err := ct.hideColorschemeMenu(); err != nil {
+		return err
+	}
+	if ct.colorschemeName == ct.State.colorschemeMenuOriginal {
+		return nil
+	}
+
+	return ct.Save()
+}
+
+// colorschemeMenuCancel restores the colorscheme from before the previews and hides the menu
+func (ct *Cointop) colorschemeMenuCancel() error {
+	ct.debuglog("colorschemeMenuCancel()")
+	if ct.colorschemeName != ct.State.colorschemeMenuOriginal {
+		if err := ct.setColorscheme(ct.State.colorschemeMenuOriginal); err != nil {
+			return err
+		}
+	}
+
+	return ct.hideColorschemeMenu()
+}
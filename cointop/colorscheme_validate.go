@@ -0,0 +1,187 @@
+package cointop
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cdyfng/coind/cointop/common/levenshtein"
+)
+
+// colorschemeKeyRegex matches the key of a line of a colorscheme file
+var colorschemeKeyRegex = regexp.MustCompile(`^\s*"?([A-Za-z0-9_-]+)"?\s*=`)
+
+// colorschemeAttrs are the suffixes of the color keys
+var colorschemeAttrs = []string{"fg", "bg", "bold", "underline"}
+
+// ColorsValidateConfig is the config options for the colors validate command
+type ColorsValidateConfig struct {
+	Filepath string
+}
+
+// ValidateColorscheme outputs the unknown keys and invalid values of the colorscheme file with their
+// line numbers. The file may also be the name of an installed colorscheme
+func ValidateColorscheme(config *ColorsValidateConfig) error {
+	path := NormalizePath(config.Filepath)
+	if info, err := os.Stat(path); (err != nil || info.IsDir()) && filepath.Ext(path) == "" {
+		path = colorschemePath(config.Filepath)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var colors map[string]interface{}
+	if _, err := toml.Decode(string(b), &colors); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	ct := &Cointop{}
+	problems := ct.colorschemeProblems(colors, colorschemeKeyLines(string(b)))
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stdout, "%s:%s\n", path, problem)
+	}
+	if len(problems) == 1 {
+		return fmt.Errorf("%s: 1 problem found", path)
+	}
+	if len(problems) > 1 {
+		return fmt.Errorf("%s: %d problems found", path, len(problems))
+	}
+
+	fmt.Fprintf(os.Stdout, "%s: ok\n", path)
+	return nil
+}
+
+// colorschemeKeyLines returns the line number of each key of the colorscheme file
+func colorschemeKeyLines(content string) map[string]int {
+	lines := make(map[string]int)
+	for i, line := range strings.Split(content, "\n") {
+		matches := colorschemeKeyRegex.FindStringSubmatch(line)
+		if len(matches) < 2 {
+			continue
+		}
+		if _, ok := lines[matches[1]]; !ok {
+			lines[matches[1]] = i + 1
+		}
+	}
+
+	return lines
+}
+
+// colorschemeNames returns the names of the colors of the default colorscheme, e.g. "table_row"
+func colorschemeNames() map[string]bool {
+	var colors map[string]interface{}
+	toml.Decode(DefaultColors, &colors)
+	names := make(map[string]bool)
+	for k := range colors {
+		if i := strings.LastIndex(k, "_"); i > 0 {
+			names[k[:i]] = true
+		}
+	}
+
+	return names
+}
+
+// colorschemeProblems returns the unknown keys and invalid values of the colors, prefixed with their
+// line numbers and sorted by line
+func (ct *Cointop) colorschemeProblems(colors map[string]interface{}, lines map[string]int) []string {
+	names := colorschemeNames()
+	var keys []string
+	for k := range colors {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if lines[keys[i]] != lines[keys[j]] {
+			return lines[keys[i]] < lines[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	var problems []string
+	for _, k := range keys {
+		if problem := ct.colorschemeValueProblem(k, colors[k], names); problem != "" {
+			problems = append(problems, fmt.Sprintf("%d: %s", lines[k], problem))
+		}
+	}
+
+	return problems
+}
+
+// colorschemeValueProblem returns why the key or its value is invalid, or an empty string if they're valid
+func (ct *Cointop) colorschemeValueProblem(key string, value interface{}, names map[string]bool) string {
+	switch key {
+	case "colorscheme":
+		if _, ok := value.(string); !ok {
+			return fmt.Sprintf("invalid value %v for colorscheme, expected a string", value)
+		}
+		return ""
+	case "extends":
+		name, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("invalid value %v for extends, expected a colorscheme name", value)
+		}
+		if _, err := ct.colorschemeColors(name); err != nil {
+			return fmt.Sprintf("cannot extend %q: %s", name, strings.Split(err.Error(), "\n")[0])
+		}
+		return ""
+	}
+
+	i := strings.LastIndex(key, "_")
+	if i < 0 || !names[key[:i]] || !isColorschemeAttr(key[i+1:]) {
+		if suggestion := closestColorschemeKey(key, names); suggestion != "" {
+			return fmt.Sprintf("unknown key %q, did you mean %q?", key, suggestion)
+		}
+		return fmt.Sprintf("unknown key %q", key)
+	}
+
+	switch key[i+1:] {
+	case "fg", "bg":
+		if name, ok := value.(string); ok && fgcolorschemeColorsMap[name] != 0 {
+			return ""
+		}
+		if _, ok := colorIndex(value); ok {
+			return ""
+		}
+		return fmt.Sprintf("invalid color %q for %s, expected a color name, a 256 color index or a \"#rrggbb\" hex color", fmt.Sprint(value), key)
+	default:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("invalid value %q for %s, expected true or false", fmt.Sprint(value), key)
+		}
+	}
+
+	return ""
+}
+
+func isColorschemeAttr(attr string) bool {
+	for _, a := range colorschemeAttrs {
+		if a == attr {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closestColorschemeKey returns the color key closest to the unknown key if it's close enough to be a typo
+func closestColorschemeKey(key string, names map[string]bool) string {
+	closest := ""
+	distance := 3
+	for name := range names {
+		for _, attr := range colorschemeAttrs {
+			candidate := fmt.Sprintf("%s_%s", name, attr)
+			d := levenshtein.DamerauLevenshteinDistance(key, candidate)
+			if d < distance || (d == distance && closest != "" && candidate < closest) {
+				closest = candidate
+				distance = d
+			}
+		}
+	}
+
+	return closest
+}
@@ -0,0 +1,452 @@
+package cointop
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/miguelmota/gocui"
+)
+
+// CommandFieldView is structure for command field view
+type CommandFieldView struct {
+	*View
+}
+
+// NewCommandFieldView returns a new command field view
+func NewCommandFieldView() *CommandFieldView {
+	return &CommandFieldView{NewView("commandfield")}
+}
+
+const (
+	commandHistoryFilename = "command_history"
+	maxCommandHistory      = 100
+)
+
+// commands are the parameterized commands of the command palette and their usage
+var commands = map[string]string{
+	"sort":      "sort <column> [asc|desc]",
+	"convert":   "convert <currency>",
+	"range":     "range <range>",
+	"goto":      "goto <symbol|name>",
+	"holdings":  "holdings <symbol|name> <amount>",
+	"watchlist": "watchlist <name>",
+}
+
+func (ct *Cointop) openCommandPalette() error {
+	ct.debuglog("openCommandPalette()")
+	ct.State.commandFieldVisible = true
+	ct.State.commandHistoryIndex = len(ct.State.commandHistory)
+	ct.SetActiveView(ct.Views.CommandField.Name())
+	return nil
+}
+
+func (ct *Cointop) cancelCommand() error {
+	ct.debuglog("cancelCommand()")
+	ct.State.commandFieldVisible = false
+	ct.SetActiveView(ct.Views.Table.Name())
+	return nil
+}
+
+func (ct *Cointop) doCommand() error {
+	ct.debuglog("doCommand()")
+	q := ct.commandInput()
+
+	ct.State.commandFieldVisible = false
+	ct.SetActiveView(ct.Views.Table.Name())
+	if q == "" {
+		return nil
+	}
+
+	ct.addCommandHistory(q)
+	if err := ct.runCommand(q); err != nil {
+		// NOTE: quitting is an error to gocui, so it's passed on to exit the main loop
+		if err == gocui.ErrQuit {
+			return err
+		}
+		ct.UpdateStatusbar(fmt.Sprintf("Command error: %s", err))
+	}
+
+	return nil
+}
+
+// commandInput returns the input in the command field without the colon
+func (ct *Cointop) commandInput() string {
+	q := ct.Views.CommandField.Backing().Buffer()
+	q = strings.TrimSpace(q)
+	q = strings.TrimPrefix(q, ":")
+	return strings.TrimSpace(q)
+}
+
+// setCommandInput replaces the input in the command field
+func (ct *Cointop) setCommandInput(q string) {
+	text := fmt.Sprintf(":%s", q)
+	ct.Views.CommandField.Backing().Clear()
+	ct.Views.CommandField.Backing().SetOrigin(0, 0)
+	ct.Views.CommandField.Backing().SetCursor(len([]rune(text)), 0)
+	fmt.Fprint(ct.Views.CommandField.Backing(), text)
+}
+
+// runCommand runs the command, which is either an action name or a parameterized command
+func (ct *Cointop) runCommand(q string) error {
+	ct.debuglog("runCommand()")
+	args := strings.Fields(q)
+	name := strings.ToLower(args[0])
+	args = args[1:]
+
+	if _, ok := commands[name]; !ok {
+		if !ct.ActionExists(name) {
+			return fmt.Errorf("unknown command %q", name)
+		}
+		if len(args) > 0 {
+			return fmt.Errorf("%s takes no arguments", name)
+		}
+		fn, _ := ct.actionHandler(name, nil)
+		return fn(ct.g, ct.Views.Table.Backing())
+	}
+
+	switch name {
+	case "sort":
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("usage: %s", commands[name])
+		}
+		return ct.sortCommand(args[0], args[1:])
+	case "convert":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: %s", commands[name])
+		}
+		return ct.convertCommand(args[0])
+	case "range":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: %s", commands[name])
+		}
+		return ct.rangeCommand(strings.Join(args, " "))
+	case "goto":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: %s", commands[name])
+		}
+		return ct.gotoCommand(strings.Join(args, " "))
+	case "holdings":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: %s", commands[name])
+		}
+		return ct.holdingsCommand(strings.Join(args[:len(args)-1], " "), args[len(args)-1])
+	case "watchlist":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: %s", commands[name])
+		}
+		return ct.toggleWatchlistCoin(args[0], ct.HighlightedRowCoin())
+	}
+
+	return nil
+}
+
+// sortCommand sorts the table by the column. The direction defaults to descending
+func (ct *Cointop) sortCommand(col string, dir []string) error {
+	ct.debuglog("sortCommand()")
+	col = strings.ToLower(col)
+	if !ct.isSortableColumn(col) {
+		return fmt.Errorf("unknown column %q", col)
+	}
+
+	desc := true
+	if len(dir) > 0 {
+		switch strings.ToLower(dir[0]) {
+		case "asc":
+			desc = false
+		case "desc":
+		default:
+			return fmt.Errorf("invalid sort direction %q", dir[0])
+		}
+	}
+
+	ct.sort(col, desc, ct.State.coins, true)
+	ct.UpdateTable()
+	return nil
+}
+
+// isSortableColumn returns true if the table can be sorted by the column
+func (ct *Cointop) isSortableColumn(col string) bool {
+	for _, name := range ct.availableTableColumns(ct.currentTableView()) {
+		if name == col {
+			return true
+		}
+	}
+
+	return false
+}
+
+// convertCommand sets the currency conversion
+func (ct *Cointop) convertCommand(currency string) error {
+	ct.debuglog("convertCommand()")
+	currency = strings.ToUpper(currency)
+	if _, ok := ct.supportedCurrencyConversions()[currency]; !ok {
+		return fmt.Errorf("unsupported currency %q", currency)
+	}
+
+	return ct.setCurrencyConverstionFn(currency)()
+}
+
+// rangeCommand sets the chart date range. The range is matched ignoring case and spaces
+func (ct *Cointop) rangeCommand(r string) error {
+	ct.debuglog("rangeCommand()")
+	for _, k := range ct.chartRanges {
+		if normalizeChartRange(k) == normalizeChartRange(r) {
+			ct.State.selectedChartRange = k
+			go ct.UpdateChart()
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown chart range %q", r)
+}
+
+// normalizeChartRange returns the chart range in lowercase without spaces
+func normalizeChartRange(r string) string {
+	return strings.ToLower(strings.Replace(r, " ", "", -1))
+}
+
+// gotoCommand highlights the row of the coin
+func (ct *Cointop) gotoCommand(q string) error {
+	ct.debuglog("gotoCommand()")
+	coin := ct.findCoin(q)
+	if coin == nil {
+		return fmt.Errorf("unknown coin %q", q)
+	}
+
+	// TODO: do this a better way (SoC)
+	ct.State.filterByFavorites = false
+	ct.State.portfolioVisible = false
+
+	for i, c := range ct.filterCoins(ct.State.allCoins) {
+		if c == coin {
+			return ct.goToGlobalIndex(i)
+		}
+	}
+
+	return fmt.Errorf("%s is hidden by the filter", coin.Symbol)
+}
+
+// holdingsCommand sets the portfolio holdings of the coin. Zero removes it from the portfolio
+func (ct *Cointop) holdingsCommand(q string, amount string) error {
+	ct.debuglog("holdingsCommand()")
+	coin := ct.findCoin(q)
+	if coin == nil {
+		return fmt.Errorf("unknown coin %q", q)
+	}
+
+	holdings, err := strconv.ParseFloat(normalizeFloatstring(amount), 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", amount)
+	}
+
+	if err := ct.setPortfolioEntry(coin.Name, holdings); err != nil {
+		return err
+	}
+	if holdings == 0 {
+		ct.removePortfolioEntry(coin.Name)
+	}
+
+	go ct.UpdateTable()
+	return nil
+}
+
+// findCoin returns the coin matching the symbol, name or ID, ignoring case
+func (ct *Cointop) findCoin(q string) *Coin {
+	ct.debuglog("findCoin()")
+	q = strings.ToLower(strings.TrimSpace(q))
+	var match *Coin
+	for _, coin := range ct.State.allCoins {
+		if coin == nil {
+			continue
+		}
+		// NOTE: symbols aren't unique, so the highest ranked coin with the symbol wins
+		if strings.ToLower(coin.Symbol) == q {
+			if match == nil || coin.Rank < match.Rank {
+				match = coin
+			}
+		}
+	}
+	if match != nil {
+		return match
+	}
+	for _, coin := range ct.State.allCoins {
+		if coin == nil {
+			continue
+		}
+		if strings.ToLower(coin.Name) == q || strings.ToLower(coin.ID) == q {
+			return coin
+		}
+	}
+
+	return nil
+}
+
+// commandCompletions returns the completions of the last word of the input
+func (ct *Cointop) commandCompletions(args []string) []string {
+	var candidates []string
+	if len(args) <= 1 {
+		for name := range commands {
+			candidates = append(candidates, name)
+		}
+		for name := range ct.ActionsMap {
+			candidates = append(candidates, name)
+		}
+	} else if len(args) == 2 {
+		switch strings.ToLower(args[0]) {
+		case "sort":
+			candidates = ct.availableTableColumns(ct.currentTableView())
+		case "convert":
+			candidates = ct.sortedSupportedCurrencyConversions()
+		case "range":
+			for _, r := range ct.chartRanges {
+				candidates = append(candidates, normalizeChartRange(r))
+			}
+		}
+	} else if len(args) == 3 && strings.ToLower(args[0]) == "sort" {
+		candidates = []string{"asc", "desc"}
+	}
+
+	prefix := ""
+	if len(args) > 0 {
+		prefix = strings.ToLower(args[len(args)-1])
+	}
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(strings.ToLower(c), prefix) {
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+
+	return matches
+}
+
+// completeCommand completes the last word of the input to the longest common prefix of the completions
+func (ct *Cointop) completeCommand() error {
+	ct.debuglog("completeCommand()")
+	input := strings.TrimPrefix(strings.TrimLeft(ct.Views.CommandField.Backing().Buffer(), " "), ":")
+	input = strings.TrimRight(input, "\n")
+	args := strings.Fields(input)
+	// NOTE: a trailing space starts a new word
+	if input == "" || strings.HasSuffix(input, " ") {
+		args = append(args, "")
+	}
+
+	matches := ct.commandCompletions(args)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	word := commonPrefix(matches)
+	if len(matches) == 1 {
+		word += " "
+	} else {
+		ct.UpdateStatusbar(strings.Join(matches, " "))
+	}
+	args[len(args)-1] = word
+	ct.setCommandInput(strings.Join(args, " "))
+	return nil
+}
+
+// commonPrefix returns the longest common prefix of the strings
+func commonPrefix(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+
+	prefix := list[0]
+	for _, s := range list[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
+
+// commandHistoryFn returns a function that replaces the input with the previous command, or the next if positive
+func (ct *Cointop) commandHistoryFn(offset int) func() error {
+	return func() error {
+		ct.debuglog("commandHistoryFn()")
+		n := len(ct.State.commandHistory)
+		i := ct.State.commandHistoryIndex + offset
+		if i < 0 || i > n {
+			return nil
+		}
+
+		ct.State.commandHistoryIndex = i
+		if i == n {
+			ct.setCommandInput("")
+			return nil
+		}
+
+		ct.setCommandInput(ct.State.commandHistory[i])
+		return nil
+	}
+}
+
+// commandHistoryPath returns the path of the command history file, which is next to the config file
+func (ct *Cointop) commandHistoryPath() string {
+	return filepath.Join(ct.configDirPath(), commandHistoryFilename)
+}
+
+// loadCommandHistory reads the command history of previous sessions
+func (ct *Cointop) loadCommandHistory() error {
+	ct.debuglog("loadCommandHistory()")
+	b, err := ioutil.ReadFile(ct.commandHistoryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var history []string
+	for _, line := range strings.Split(string(b), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			history = append(history, line)
+		}
+	}
+	if len(history) > maxCommandHistory {
+		history = history[len(history)-maxCommandHistory:]
+	}
+
+	ct.State.commandHistory = history
+	return nil
+}
+
+// addCommandHistory appends the command to the history and saves it
+func (ct *Cointop) addCommandHistory(q string) {
+	ct.debuglog("addCommandHistory()")
+	history := ct.State.commandHistory
+	// NOTE: repeated commands are only kept once
+	if len(history) > 0 && history[len(history)-1] == q {
+		return
+	}
+
+	history = append(history, q)
+	if len(history) > maxCommandHistory {
+		history = history[len(history)-maxCommandHistory:]
+	}
+	ct.State.commandHistory = history
+
+	if err := ct.saveCommandHistory(); err != nil {
+		ct.debuglog(fmt.Sprintf("command history error: %s", err))
+	}
+}
+
+// saveCommandHistory writes the command history to the history file
+func (ct *Cointop) saveCommandHistory() error {
+	ct.debuglog("saveCommandHistory()")
+	if err := ct.makeConfigDir(); err != nil {
+		return err
+	}
+
+	b := []byte(strings.Join(ct.State.commandHistory, "\n") + "\n")
+	return ioutil.WriteFile(ct.commandHistoryPath(), b, fileperm)
+}
@@ -46,7 +46,7 @@ func (s *Service) getLimitedCoinData(convert string, offset int) ([]apitypes.Coi
 	ids := []string{}
 	perPage := 250
 	page := offset
-	sparkline := false
+	sparkline := true
 	pcp := geckoTypes.PriceChangePercentageObject
 	priceChangePercentage := []string{pcp.PCP1h, pcp.PCP24h, pcp.PCP7d, pcp.PCP30d, pcp.PCP1y}
 	order := geckoTypes.OrderTypeObject.MarketCapDesc
@@ -94,6 +94,11 @@ func (s *Service) getLimitedCoinData(convert string, offset int) ([]apitypes.Coi
 				percentChange1Y = *item.PriceChangePercentage1yInCurrency
 			}
 
+			var sparkline7D []float64
+			if item.SparklineIn7d != nil {
+				sparkline7D = item.SparklineIn7d.Price
+			}
+
 			availableSupply := item.CirculatingSupply
 			totalSupply := item.TotalSupply
 			if totalSupply == 0 {
@@ -116,6 +121,7 @@ func (s *Service) getLimitedCoinData(convert string, offset int) ([]apitypes.Coi
 				PercentChange1Y:  util.FormatPercentChange(percentChange1Y),
 				Volume24H:        util.FormatVolume(item.TotalVolume),
 				LastUpdated:      util.FormatLastUpdated(item.LastUpdated),
+				Sparkline7D:      sparkline7D,
 			})
 		}
 	}
@@ -168,6 +174,18 @@ func (s *Service) GetCoinGraphData(convert, symbol, name string, start, end int6
 		}
 	}
 
+	if chart.TotalVolumes != nil {
+		for _, item := range *chart.TotalVolumes {
+			timestamp := float64(item[0])
+			volume := float64(item[1])
+
+			volumeCoin = append(volumeCoin, []float64{
+				timestamp,
+				volume,
+			})
+		}
+	}
+
 	ret.MarketCapByAvailableSupply = marketCap
 	ret.PriceBTC = priceBTC
 	ret.Price = priceCoin
@@ -176,6 +194,46 @@ func (s *Service) GetCoinGraphData(convert, symbol, name string, start, end int6
 	return ret, nil
 }
 
+// ohlcDays are the days supported by the OHLC endpoint
+var ohlcDays = []int{1, 7, 14, 30, 90, 180, 365}
+
+// GetCoinOHLCData gets coin OHLC data
+func (s *Service) GetCoinOHLCData(convert, symbol, name string, start, end int64) (apitypes.CoinOHLC, error) {
+	ret := apitypes.CoinOHLC{}
+	convertTo := strings.ToLower(convert)
+	if convertTo == "" {
+		convertTo = "usd"
+	}
+
+	// NOTE: the OHLC endpoint only supports a few day ranges so the closest range
+	// covering the requested one is used.
+	days := "max"
+	calcDays := util.CalcDays(start, end)
+	for _, d := range ohlcDays {
+		if calcDays <= d {
+			days = strconv.Itoa(d)
+			break
+		}
+	}
+
+	ohlc, err := s.client.CoinsIDOHLC(util.NameToSlug(name), convertTo, days)
+	if err != nil {
+		return ret, err
+	}
+
+	var items [][]float64
+	for _, item := range *ohlc {
+		// NOTE: timestamps are in milliseconds
+		if int64(item[0]/1000) < start {
+			continue
+		}
+		items = append(items, []float64{item[0], item[1], item[2], item[3], item[4]})
+	}
+
+	ret.OHLC = items
+	return ret, nil
+}
+
 // GetGlobalMarketGraphData gets global market graph data
 func (s *Service) GetGlobalMarketGraphData(convert string, start int64, end int64) (apitypes.MarketGraph, error) {
 	days := strconv.Itoa(util.CalcDays(start, end))
@@ -260,6 +318,32 @@ func (s *Service) Price(name string, convert string) (float64, error) {
 	return 0, ErrNotFound
 }
 
+// GetExchangeRate returns the amount of convertTo units for one unit of convertFrom
+func (s *Service) GetExchangeRate(convertFrom, convertTo string) (float64, error) {
+	convertFrom = strings.ToLower(convertFrom)
+	convertTo = strings.ToLower(convertTo)
+	if convertFrom == convertTo {
+		return 1, nil
+	}
+
+	rates, err := s.client.ExchangeRates()
+	if err != nil {
+		return 0, err
+	}
+
+	// NOTE: rates are relative to BTC
+	from, ok := (*rates)[convertFrom]
+	if !ok || from.Value == 0 {
+		return 0, ErrNotFound
+	}
+	to, ok := (*rates)[convertTo]
+	if !ok {
+		return 0, ErrNotFound
+	}
+
+	return to.Value / from.Value, nil
+}
+
 // CoinLink returns the URL link for the coin
 func (s *Service) CoinLink(name string) string {
 	slug := util.NameToSlug(name)
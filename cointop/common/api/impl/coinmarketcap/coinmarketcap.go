@@ -19,6 +19,9 @@ var ErrQuoteNotFound = errors.New("Quote not found")
 // ErrPingFailed is the error for when pinging the API fails
 var ErrPingFailed = errors.New("Failed to ping")
 
+// ErrNotSupported is the error for when the API doesn't support the request
+var ErrNotSupported = errors.New("Not supported")
+
 // Service service
 type Service struct {
 	client *cmc.Client
@@ -128,6 +131,11 @@ func (s *Service) GetCoinGraphData(convert, symbol string, name string, start in
 	return ret, nil
 }
 
+// GetCoinOHLCData gets coin OHLC data. It's not supported by the CoinMarketCap API
+func (s *Service) GetCoinOHLCData(convert, symbol string, name string, start int64, end int64) (apitypes.CoinOHLC, error) {
+	return apitypes.CoinOHLC{}, ErrNotSupported
+}
+
 // GetGlobalMarketGraphData gets global market graph data
 func (s *Service) GetGlobalMarketGraphData(convert string, start int64, end int64) (apitypes.MarketGraph, error) {
 	ret := apitypes.MarketGraph{}
@@ -184,6 +192,37 @@ func (s *Service) Price(name string, convert string) (float64, error) {
 	return util.FormatPrice(price, convert), nil
 }
 
+// GetExchangeRate returns the amount of convertTo units for one unit of convertFrom
+func (s *Service) GetExchangeRate(convertFrom, convertTo string) (float64, error) {
+	convertFrom = strings.ToUpper(convertFrom)
+	convertTo = strings.ToUpper(convertTo)
+	if convertFrom == convertTo {
+		return 1, nil
+	}
+
+	// NOTE: rates are derived from the BTC price in both currencies
+	from, err := cmcv2.Price(&cmcv2.PriceOptions{
+		Symbol:  "BTC",
+		Convert: convertFrom,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if from == 0 {
+		return 0, ErrQuoteNotFound
+	}
+
+	to, err := cmcv2.Price(&cmcv2.PriceOptions{
+		Symbol:  "BTC",
+		Convert: convertTo,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return to / from, nil
+}
+
 // CoinLink returns the URL link for the coin
 func (s *Service) CoinLink(name string) string {
 	slug := util.NameToSlug(name)
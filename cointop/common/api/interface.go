@@ -9,6 +9,7 @@ type Interface interface {
 	Ping() error
 	GetAllCoinData(convert string, ch chan []types.Coin) error
 	GetCoinGraphData(convert string, symbol string, name string, start int64, end int64) (types.CoinGraph, error)
+	GetCoinOHLCData(convert string, symbol string, name string, start int64, end int64) (types.CoinOHLC, error)
 	GetGlobalMarketGraphData(convert string, start int64, end int64) (types.MarketGraph, error)
 	GetGlobalMarketData(convert string) (types.GlobalMarketData, error)
 	//GetCoinData(coin string) (types.Coin, error)
@@ -18,4 +19,5 @@ type Interface interface {
 	CoinLink(name string) string
 	SupportedCurrencies() []string
 	Price(name string, convert string) (float64, error)
+	GetExchangeRate(convertFrom string, convertTo string) (float64, error)
 }
@@ -17,6 +17,8 @@ type Coin struct {
 	PercentChange30D float64
 	PercentChange1Y  float64
 	LastUpdated      string
+	// prices of the last 7 days, oldest first
+	Sparkline7D []float64
 }
 
 // GlobalMarketData struct
@@ -37,6 +39,12 @@ type CoinGraph struct {
 	Volume                     [][]float64
 }
 
+// CoinOHLC struct
+type CoinOHLC struct {
+	// OHLC items are [timestamp, open, high, low, close]
+	OHLC [][]float64
+}
+
 // Market struct
 type Market struct {
 	Rank          int
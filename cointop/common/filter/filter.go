@@ -0,0 +1,535 @@
+// Package filter parses and evaluates filter expressions such as
+// `marketcap > 1e9 && 24hchange > 5 && symbol !~ "USD"`.
+//
+// Grammar:
+//
+//	expr       = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" expr ")" | "@" name | comparison
+//	comparison = field op value
+//	op         = ">" | ">=" | "<" | "<=" | "==" | "!=" | "=~" | "!~"
+//	value      = number | string | word
+//
+// Strings are quoted with double or single quotes. Words are unquoted
+// values such as BTC or true. String comparisons are case insensitive and
+// "=~" and "!~" match a regular expression. "@name" is replaced with the
+// named filter of that name, see ParseNamed.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Fields resolves the value of a field by name. Values must be float64, int, string or bool
+type Fields interface {
+	Field(name string) (interface{}, bool)
+}
+
+// FieldsMap is a map implementation of Fields
+type FieldsMap map[string]interface{}
+
+// Field returns the value of the field
+func (m FieldsMap) Field(name string) (interface{}, bool) {
+	v, ok := m[name]
+	return v, ok
+}
+
+// SyntaxError is an error in the expression syntax
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+// Error returns the error message
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos+1, e.Msg)
+}
+
+// Expr is a parsed filter expression
+type Expr struct {
+	src  string
+	root node
+}
+
+// String returns the source of the expression
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Match returns true if the fields match the expression
+func (e *Expr) Match(fields Fields) (bool, error) {
+	return e.root.eval(fields)
+}
+
+// Validate checks that every field in the expression exists and is comparable with its value
+func (e *Expr) Validate(fields Fields) error {
+	return e.root.validate(fields)
+}
+
+// Parse parses a filter expression
+func Parse(src string) (*Expr, error) {
+	return ParseNamed(src, nil)
+}
+
+// ParseNamed parses a filter expression in which "@name" references the named filter expression of that
+// name. Named filters may reference other named filters, but not themselves
+func ParseNamed(src string, named map[string]string) (*Expr, error) {
+	root, err := parse(src, named, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expr{src: strings.TrimSpace(src), root: root}, nil
+}
+
+// parse parses the expression. The seen names are the named filters being parsed, which are used to detect cycles
+func parse(src string, named map[string]string, seen []string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, named: named, seen: seen}
+	if p.peek().kind == tokenEOF {
+		return nil, &SyntaxError{0, "empty expression"}
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &SyntaxError{t.pos, fmt.Sprintf("unexpected %s", t)}
+	}
+
+	return root, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenNumber
+	tokenString
+	tokenOp
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+	tokenNamed
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	case tokenNamed:
+		return fmt.Sprintf("%q", "@"+t.text)
+	}
+
+	return fmt.Sprintf("%q", t.text)
+}
+
+var numberRegex = regexp.MustCompile(`^-?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?`)
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' ||
+		(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case strings.HasPrefix(src[i:], "&&"):
+			tokens = append(tokens, token{tokenAnd, "&&", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "||"):
+			tokens = append(tokens, token{tokenOr, "||", i})
+			i += 2
+		case strings.HasPrefix(src[i:], ">="), strings.HasPrefix(src[i:], "<="),
+			strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="),
+			strings.HasPrefix(src[i:], "=~"), strings.HasPrefix(src[i:], "!~"):
+			tokens = append(tokens, token{tokenOp, src[i : i+2], i})
+			i += 2
+		case c == '>' || c == '<':
+			tokens = append(tokens, token{tokenOp, string(c), i})
+			i++
+		case c == '!':
+			tokens = append(tokens, token{tokenNot, "!", i})
+			i++
+		case c == '"' || c == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for ; i < len(src) && src[i] != c; i++ {
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+				}
+				b.WriteByte(src[i])
+			}
+			if i >= len(src) {
+				return nil, &SyntaxError{start, "unterminated string"}
+			}
+			i++
+			tokens = append(tokens, token{tokenString, b.String(), start})
+		case c == '@':
+			start := i
+			i++
+			for i < len(src) && isWordChar(src[i]) {
+				i++
+			}
+			if i == start+1 {
+				return nil, &SyntaxError{start, "expected a named filter after \"@\""}
+			}
+			tokens = append(tokens, token{tokenNamed, src[start+1 : i], start})
+		case isWordChar(c):
+			start := i
+			for i < len(src) && isWordChar(src[i]) {
+				i++
+			}
+			// NOTE: a number is only a number if it's not the prefix of a word, e.g. 24hchange
+			if m := numberRegex.FindString(src[start:]); m != "" {
+				if start+len(m) == i {
+					tokens = append(tokens, token{tokenNumber, m, start})
+					continue
+				}
+			}
+			tokens = append(tokens, token{tokenWord, src[start:i], start})
+		default:
+			return nil, &SyntaxError{i, fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "", len(src)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	named  map[string]string
+	seen   []string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNot:
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{n}, nil
+	case tokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, &SyntaxError{t.pos, fmt.Sprintf("expected \")\", got %s", t)}
+		}
+		return n, nil
+	case tokenWord:
+		return p.parseComparison(t)
+	case tokenNamed:
+		return p.parseNamed(t)
+	}
+
+	return nil, &SyntaxError{t.pos, fmt.Sprintf("expected field name, got %s", t)}
+}
+
+// parseNamed parses the expression of the named filter. The errors in it are reported at the reference
+func (p *parser) parseNamed(t token) (node, error) {
+	for _, name := range p.seen {
+		if name == t.text {
+			return nil, &SyntaxError{t.pos, fmt.Sprintf("named filter @%s references itself", t.text)}
+		}
+	}
+	src, ok := p.named[t.text]
+	if !ok {
+		return nil, &SyntaxError{t.pos, fmt.Sprintf("unknown named filter @%s", t.text)}
+	}
+
+	n, err := parse(src, p.named, append(p.seen[:len(p.seen):len(p.seen)], t.text))
+	if err != nil {
+		return nil, &SyntaxError{t.pos, fmt.Sprintf("in named filter @%s: %s", t.text, err)}
+	}
+
+	return &namedNode{t.text, t.pos, n}, nil
+}
+
+func (p *parser) parseComparison(field token) (node, error) {
+	op := p.next()
+	if op.kind != tokenOp {
+		return nil, &SyntaxError{op.pos, fmt.Sprintf("expected operator after %q, got %s", field.text, op)}
+	}
+
+	v := p.next()
+	n := &compareNode{field: strings.ToLower(field.text), op: op.text, raw: v.text, pos: field.pos}
+	switch v.kind {
+	case tokenNumber:
+		f, err := strconv.ParseFloat(v.text, 64)
+		if err != nil {
+			return nil, &SyntaxError{v.pos, fmt.Sprintf("invalid number %q", v.text)}
+		}
+		n.value = f
+	case tokenString:
+		n.value = v.text
+	case tokenWord:
+		switch strings.ToLower(v.text) {
+		case "true":
+			n.value = true
+		case "false":
+			n.value = false
+		default:
+			n.value = v.text
+		}
+	default:
+		return nil, &SyntaxError{v.pos, fmt.Sprintf("expected value after %q, got %s", op.text, v)}
+	}
+
+	if op.text == "=~" || op.text == "!~" {
+		re, err := regexp.Compile("(?i)" + n.raw)
+		if err != nil {
+			return nil, &SyntaxError{v.pos, fmt.Sprintf("invalid regular expression %q", n.raw)}
+		}
+		n.re = re
+	}
+
+	return n, nil
+}
+
+type node interface {
+	eval(fields Fields) (bool, error)
+	validate(fields Fields) error
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n *andNode) eval(fields Fields) (bool, error) {
+	ok, err := n.left.eval(fields)
+	if err != nil || !ok {
+		return false, err
+	}
+	return n.right.eval(fields)
+}
+
+func (n *andNode) validate(fields Fields) error {
+	if err := n.left.validate(fields); err != nil {
+		return err
+	}
+	return n.right.validate(fields)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n *orNode) eval(fields Fields) (bool, error) {
+	ok, err := n.left.eval(fields)
+	if err != nil || ok {
+		return ok, err
+	}
+	return n.right.eval(fields)
+}
+
+func (n *orNode) validate(fields Fields) error {
+	if err := n.left.validate(fields); err != nil {
+		return err
+	}
+	return n.right.validate(fields)
+}
+
+type notNode struct {
+	n node
+}
+
+func (n *notNode) eval(fields Fields) (bool, error) {
+	ok, err := n.n.eval(fields)
+	return !ok, err
+}
+
+func (n *notNode) validate(fields Fields) error {
+	return n.n.validate(fields)
+}
+
+// namedNode is a named filter in the expression. The position is where it's referenced
+type namedNode struct {
+	name string
+	pos  int
+	n    node
+}
+
+func (n *namedNode) eval(fields Fields) (bool, error) {
+	return n.n.eval(fields)
+}
+
+func (n *namedNode) validate(fields Fields) error {
+	if err := n.n.validate(fields); err != nil {
+		return &SyntaxError{n.pos, fmt.Sprintf("in named filter @%s: %s", n.name, err)}
+	}
+	return nil
+}
+
+type compareNode struct {
+	field string
+	op    string
+	value interface{}
+	raw   string
+	re    *regexp.Regexp
+	pos   int
+}
+
+func (n *compareNode) validate(fields Fields) error {
+	_, err := n.eval(fields)
+	return err
+}
+
+func (n *compareNode) eval(fields Fields) (bool, error) {
+	v, ok := fields.Field(n.field)
+	if !ok {
+		return false, &SyntaxError{n.pos, fmt.Sprintf("unknown field %q", n.field)}
+	}
+
+	switch fv := v.(type) {
+	case int:
+		return n.compareNumber(float64(fv))
+	case float64:
+		return n.compareNumber(fv)
+	case string:
+		return n.compareString(fv)
+	case bool:
+		return n.compareBool(fv)
+	}
+
+	return false, &SyntaxError{n.pos, fmt.Sprintf("field %q can't be compared", n.field)}
+}
+
+func (n *compareNode) compareNumber(a float64) (bool, error) {
+	b, ok := n.value.(float64)
+	if !ok {
+		return false, &SyntaxError{n.pos, fmt.Sprintf("field %q must be compared with a number", n.field)}
+	}
+
+	switch n.op {
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case "==":
+		return a == b, nil
+	case "!=":
+		return a != b, nil
+	}
+
+	return false, &SyntaxError{n.pos, fmt.Sprintf("operator %q is not supported for number field %q", n.op, n.field)}
+}
+
+func (n *compareNode) compareString(a string) (bool, error) {
+	if _, ok := n.value.(bool); ok {
+		return false, &SyntaxError{n.pos, fmt.Sprintf("field %q must be compared with a string", n.field)}
+	}
+
+	switch n.op {
+	case "==":
+		return strings.EqualFold(a, n.raw), nil
+	case "!=":
+		return !strings.EqualFold(a, n.raw), nil
+	case "=~":
+		return n.re.MatchString(a), nil
+	case "!~":
+		return !n.re.MatchString(a), nil
+	}
+
+	return false, &SyntaxError{n.pos, fmt.Sprintf("operator %q is not supported for string field %q", n.op, n.field)}
+}
+
+func (n *compareNode) compareBool(a bool) (bool, error) {
+	b, ok := n.value.(bool)
+	if !ok {
+		return false, &SyntaxError{n.pos, fmt.Sprintf("field %q must be compared with true or false", n.field)}
+	}
+
+	switch n.op {
+	case "==":
+		return a == b, nil
+	case "!=":
+		return a != b, nil
+	}
+
+	return false, &SyntaxError{n.pos, fmt.Sprintf("operator %q is not supported for boolean field %q", n.op, n.field)}
+}
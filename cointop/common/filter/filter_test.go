@@ -0,0 +1,219 @@
+package filter
+
+import (
+	"testing"
+)
+
+var fields = FieldsMap{
+	"symbol":    "BTC",
+	"name":      "Bitcoin",
+	"rank":      1,
+	"price":     9000.5,
+	"marketcap": 1.6e11,
+	"24hchange": 5.5,
+	"1hchange":  -0.25,
+	"favorite":  true,
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`marketcap > 1e9`, true},
+		{`marketcap > 1e9 && 24hchange > 5 && symbol !~ "USD"`, true},
+		{`marketcap > 1e9 && 24hchange > 6`, false},
+		{`marketcap < 1e9 || 24hchange > 5`, true},
+		{`marketcap < 1e9 || 24hchange > 6`, false},
+		{`1hchange < 0`, true},
+		{`1hchange >= -0.25 && 1hchange <= -0.25`, true},
+		{`rank == 1`, true},
+		{`rank != 1`, false},
+		{`price >= 9000.5`, true},
+		{`price > .5`, true},
+		{`symbol == btc`, true},
+		{`symbol == "BTC"`, true},
+		{`symbol == 'btc'`, true},
+		{`symbol != BTC`, false},
+		{`name =~ "^bit"`, true},
+		{`name =~ "coin$"`, true},
+		{`name !~ "eth"`, true},
+		{`favorite == true`, true},
+		{`favorite != true`, false},
+		{`!(rank == 1)`, false},
+		{`!rank == 2`, true},
+		{`(rank == 2 || rank == 1) && symbol == BTC`, true},
+		{`rank == 2 || rank == 1 && symbol == ETH`, false},
+		{`(rank == 2 || rank == 1) && symbol == ETH`, false},
+		{`SYMBOL == BTC`, true},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		got, err := expr.Match(fields)
+		if err != nil {
+			t.Errorf("Match(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+	}{
+		{``, 0},
+		{`   `, 0},
+		{`marketcap`, 9},
+		{`marketcap >`, 11},
+		{`> 5`, 0},
+		{`marketcap > 1 &&`, 16},
+		{`(marketcap > 1`, 14},
+		{`marketcap > 1)`, 13},
+		{`marketcap > 1 rank`, 14},
+		{`symbol == "BTC`, 10},
+		{`symbol =~ "("`, 10},
+		{`marketcap > 1 & rank < 2`, 14},
+		{`marketcap > 1 $`, 14},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.expr)
+		if err == nil {
+			t.Errorf("Parse(%q) expected error", tt.expr)
+			continue
+		}
+		serr, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("Parse(%q) returned %T, want *SyntaxError", tt.expr, err)
+			continue
+		}
+		if serr.Pos != tt.pos {
+			t.Errorf("Parse(%q) error position = %d, want %d (%v)", tt.expr, serr.Pos, tt.pos, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		expr string
+		ok   bool
+	}{
+		{`marketcap > 1e9 && symbol == BTC`, true},
+		{`volume > 1`, false},
+		{`marketcap > BTC`, false},
+		{`marketcap =~ "1"`, false},
+		{`symbol > 1`, false},
+		{`symbol == true`, false},
+		{`favorite == 1`, false},
+		{`favorite > true`, false},
+		// NOTE: validation doesn't short circuit
+		{`rank == 1 || volume > 1`, false},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		err = expr.Validate(fields)
+		if (err == nil) != tt.ok {
+			t.Errorf("Validate(%q) = %v, want ok %v", tt.expr, err, tt.ok)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	expr, err := Parse("  rank < 10 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expr.String() != "rank < 10" {
+		t.Errorf("String() = %q, want %q", expr.String(), "rank < 10")
+	}
+}
+
+func TestParseNamed(t *testing.T) {
+	named := map[string]string{
+		"big":      "marketcap > 1e9",
+		"movers":   "24hchange > 5",
+		"bigmover": "@big && @movers",
+		"a":        "@b",
+		"b":        "@a",
+		"self":     "@self && rank == 1",
+		"broken":   "@missing",
+		"invalid":  "rank >",
+		"unknown":  "volume > 1",
+	}
+
+	matches := []struct {
+		expr string
+		want bool
+	}{
+		{`@big`, true},
+		{`@bigmover && rank == 1`, true},
+		{`!@movers`, false},
+		{`(@big || rank == 2) && !(@movers && rank == 2)`, true},
+		{`name == "foo@bar" || @big`, true},
+		{`name =~ "@big"`, false},
+	}
+
+	for _, tt := range matches {
+		expr, err := ParseNamed(tt.expr, named)
+		if err != nil {
+			t.Errorf("ParseNamed(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		got, err := expr.Match(fields)
+		if err != nil {
+			t.Errorf("Match(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	// NOTE: the errors in the named filters are reported where they're referenced
+	errors := []struct {
+		expr string
+		pos  int
+	}{
+		{`rank == 1 && @nope`, 13},
+		{`@a`, 0},
+		{`rank == 1 || @self`, 13},
+		{`@broken`, 0},
+		{`  @invalid`, 2},
+		{`@ && rank == 1`, 0},
+		{`rank == 1 && @`, 13},
+	}
+
+	for _, tt := range errors {
+		_, err := ParseNamed(tt.expr, named)
+		serr, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("ParseNamed(%q) returned %v, want *SyntaxError", tt.expr, err)
+			continue
+		}
+		if serr.Pos != tt.pos {
+			t.Errorf("ParseNamed(%q) error position = %d, want %d (%v)", tt.expr, serr.Pos, tt.pos, err)
+		}
+	}
+
+	expr, err := ParseNamed(`rank == 1 && @unknown`, named)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serr, ok := expr.Validate(fields).(*SyntaxError); !ok || serr.Pos != 13 {
+		t.Errorf("Validate() = %v, want an error at column 14", serr)
+	}
+}
@@ -0,0 +1,69 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Score returns how well the pattern fuzzy matches the target, where higher is better.
+// It returns -1 if the pattern characters don't appear in the target in order.
+// The comparison is case insensitive.
+func Score(pattern, target string) int {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(target))
+	if len(p) == 0 {
+		return 0
+	}
+	if len(p) > len(t) {
+		return -1
+	}
+	if string(p) == string(t) {
+		return 1000
+	}
+
+	score := 0
+	if strings.HasPrefix(string(t), string(p)) {
+		score += 200
+	}
+
+	ti := 0
+	prev := -1
+	for _, r := range p {
+		found := false
+		for ; ti < len(t); ti++ {
+			if t[ti] != r {
+				continue
+			}
+			score += 10
+			if prev >= 0 && ti == prev+1 {
+				// consecutive characters
+				score += 15
+			} else if prev >= 0 {
+				// gap between matched characters
+				score -= ti - prev - 1
+			} else {
+				// distance to the first matched character
+				score -= ti
+			}
+			if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+				// start of a word
+				score += 10
+			}
+			prev = ti
+			ti++
+			found = true
+			break
+		}
+		if !found {
+			return -1
+		}
+	}
+
+	// prefer shorter targets
+	score -= len(t) - len(p)
+	if score < 0 {
+		score = 0
+	}
+
+	return score
+}
@@ -0,0 +1,54 @@
+package fuzzy
+
+import "testing"
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		target  string
+		want    int
+	}{
+		{"", "bitcoin", 0},
+		{"btc", "BTC", 1000},
+		{"Bitcoin", "bitcoin", 1000},
+		{"btc", "bitcoin", 50},
+		{"eth", "ethereum", 265},
+		{"cash", "bitcoin cash", 54},
+		{"bitcoinx", "bitcoin", -1},
+		{"xyz", "bitcoin", -1},
+		{"tb", "bitcoin", -1},
+	}
+
+	for _, test := range tests {
+		got := Score(test.pattern, test.target)
+		if got != test.want {
+			t.Errorf("Score(%q, %q) = %d, want %d", test.pattern, test.target, got, test.want)
+		}
+	}
+}
+
+func TestScoreOrdering(t *testing.T) {
+	tests := []struct {
+		pattern string
+		better  string
+		worse   string
+	}{
+		// exact matches beat everything
+		{"eth", "ETH", "ethereum"},
+		// prefixes beat matches inside the target
+		{"bit", "bitcoin", "wrapped bitcoin"},
+		// consecutive characters beat scattered ones
+		{"coin", "bitcoin", "cosmos chain"},
+		// word starts beat matches inside a word
+		{"coin", "usd coin", "usdecoin"},
+		// shorter targets beat longer ones
+		{"doge", "dogecoin", "dogecoin classic"},
+	}
+
+	for _, test := range tests {
+		better, worse := Score(test.pattern, test.better), Score(test.pattern, test.worse)
+		if better <= worse {
+			t.Errorf("Score(%q, %q) = %d, want more than Score(%q, %q) = %d", test.pattern, test.better, better, test.pattern, test.worse, worse)
+		}
+	}
+}
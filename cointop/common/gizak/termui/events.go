@@ -48,7 +48,7 @@ func evtKbd(e termbox.Event) EvtKbd {
 
 		if e.Key <= 0x7F {
 			pre = "C-"
-			k = string('a' - 1 + int(e.Key))
+			k = string(rune('a' - 1 + int(e.Key)))
 			kmap := map[termbox.Key][2]string{
 				termbox.KeyCtrlSpace:     {"C-", "<space>"},
 				termbox.KeyBackspace:     {"", "<backspace>"},
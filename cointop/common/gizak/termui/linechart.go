@@ -50,11 +50,18 @@ var rSingleBraille = [4]rune{'\u2880', '⠠', '⠐', '⠈'}
 type LineChart struct {
 	Block
 	Data          []float64
-	DataLabels    []string // if unset, the data indices will be used
-	Mode          string   // braille | dot
+	MultiData     [][]float64 // if set, one line is drawn per series instead of Data. NaN values are gaps
+	Candles       []Candle    // drawn instead of Data in candle mode
+	DataLabels    []string    // if unset, the data indices will be used
+	Mode          string      // braille | dot | candle
+	YScale        string      // linear(default) | log | percent, where percent is the change from the first value
 	DotStyle      rune
 	LineColor     Attribute
-	scale         float64 // data span per cell on y-axis
+	LineColors    []Attribute          // colors of the MultiData lines, LineColor is used if unset
+	UpColor       Attribute            // color of the candles closing higher than they opened
+	DownColor     Attribute            // color of the candles closing lower than they opened
+	YLabelFormat  func(float64) string // formats the y-axis labels, two decimals are used if unset
+	scale         float64              // data span per cell on y-axis
 	AxesColor     Attribute
 	drawingX      int
 	drawingY      int
@@ -70,6 +77,16 @@ type LineChart struct {
 	maxY          float64
 	minY          float64
 	autoLabels    bool
+	candles       []Candle
+	scaleBase     float64
+}
+
+// Candle is the open, high, low and close of a candle in candle mode
+type Candle struct {
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
 }
 
 // NewLineChart returns a new LineChart with current theme.
@@ -79,6 +96,8 @@ func NewLineChart() *LineChart {
 	lc.LineColor = ThemeAttr("linechart.line.fg")
 	lc.Mode = "braille"
 	lc.DotStyle = '•'
+	lc.UpColor = ColorGreen
+	lc.DownColor = ColorRed
 	lc.axisXLabelGap = 2
 	lc.axisYLabelGap = 1
 	lc.bottomValue = math.Inf(1)
@@ -86,43 +105,118 @@ func NewLineChart() *LineChart {
 	return lc
 }
 
+// series returns the data series to draw
+func (lc *LineChart) series() [][]float64 {
+	if lc.Mode == "candle" {
+		if len(lc.Candles) == 0 {
+			return nil
+		}
+		// NOTE: the lows and highs bound the y-axis
+		lows := make([]float64, len(lc.Candles))
+		highs := make([]float64, len(lc.Candles))
+		for i, c := range lc.Candles {
+			lows[i] = c.Low
+			highs[i] = c.High
+		}
+		return [][]float64{lows, highs}
+	}
+	if len(lc.MultiData) > 0 {
+		return lc.MultiData
+	}
+	if len(lc.Data) > 0 {
+		return [][]float64{lc.Data}
+	}
+	return nil
+}
+
+// scaled returns the value on the y-axis scale. Values which can't be scaled are NaN
+func (lc *LineChart) scaled(d float64) float64 {
+	switch lc.YScale {
+	case "log":
+		if d <= 0 {
+			return math.NaN()
+		}
+		return math.Log10(d)
+	case "percent":
+		if lc.scaleBase == 0 {
+			return math.NaN()
+		}
+		return (d/lc.scaleBase - 1) * 100
+	}
+	return d
+}
+
+// calcScaleBase sets the value the percent scale is relative to, which is the
+// first value of the line drawn on top or the open of the first candle
+func (lc *LineChart) calcScaleBase() {
+	series := lc.series()
+	lc.scaleBase = firstValue(series[len(series)-1], func(v float64) float64 { return v })
+	if lc.Mode == "candle" {
+		lc.scaleBase = lc.Candles[0].Open
+	}
+}
+
+// seriesColor returns the line color of the series
+func (lc *LineChart) seriesColor(i int) Attribute {
+	if len(lc.MultiData) > 0 && i < len(lc.LineColors) {
+		return lc.LineColors[i]
+	}
+	return lc.LineColor
+}
+
 // one cell contains two data points
 // so the capacity is 2x as dot-mode
-func (lc *LineChart) renderBraille() Buffer {
+func (lc *LineChart) renderBraille(data []float64, color Attribute) Buffer {
 	buf := NewBuffer()
 
 	// return: b -> which cell should the point be in
 	//         m -> in the cell, divided into 4 equal height levels, which subcell?
 	getPos := func(d float64) (b, m int) {
-		cnt4 := int((d-lc.bottomValue)/(lc.scale/4) + 0.5)
+		cnt4 := int((lc.scaled(d)-lc.bottomValue)/(lc.scale/4) + 0.5)
 		b = cnt4 / 4
 		m = cnt4 % 4
 		return
 	}
 	// plot points
-	for i := 0; 2*i+1 < len(lc.Data) && i < lc.axisXWidth; i++ {
-		b0, m0 := getPos(lc.Data[2*i])
-		b1, m1 := getPos(lc.Data[2*i+1])
-
-		if b0 == b1 {
+	for i := 0; 2*i+1 < len(data) && i < lc.axisXWidth; i++ {
+		// NOTE: NaN values are gaps in the line
+		nan0, nan1 := math.IsNaN(lc.scaled(data[2*i])), math.IsNaN(lc.scaled(data[2*i+1]))
+		if nan0 && nan1 {
+			continue
+		}
+		b0, m0 := getPos(data[2*i])
+		b1, m1 := getPos(data[2*i+1])
+
+		if nan0 || nan1 {
+			var b int
+			var single rune
+			if nan0 {
+				b, single = b1, rSingleBraille[m1]
+			} else {
+				b, single = b0, lSingleBraille[m0]
+			}
+			x := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
+			y := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - b
+			buf.Set(x, y, Cell{Ch: single, Fg: color, Bg: lc.Bg})
+		} else if b0 == b1 {
 			c := Cell{
 				Ch: braillePatterns[[2]int{m0, m1}],
 				Bg: lc.Bg,
-				Fg: lc.LineColor,
+				Fg: color,
 			}
 			y := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - b0
 			x := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
 			buf.Set(x, y, c)
 		} else {
 			c0 := Cell{Ch: lSingleBraille[m0],
-				Fg: lc.LineColor,
+				Fg: color,
 				Bg: lc.Bg}
 			x0 := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
 			y0 := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - b0
 			buf.Set(x0, y0, c0)
 
 			c1 := Cell{Ch: rSingleBraille[m1],
-				Fg: lc.LineColor,
+				Fg: color,
 				Bg: lc.Bg}
 			x1 := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
 			y1 := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - b1
@@ -133,17 +227,22 @@ func (lc *LineChart) renderBraille() Buffer {
 	return buf
 }
 
-func (lc *LineChart) renderDot() Buffer {
+func (lc *LineChart) renderDot(data []float64, color Attribute) Buffer {
 	buf := NewBuffer()
 	lasty := -1 // previous y val
-	for i := 0; i < len(lc.Data) && i < lc.axisXWidth; i++ {
+	for i := 0; i < len(data) && i < lc.axisXWidth; i++ {
+		// NOTE: NaN values are gaps in the line
+		if math.IsNaN(lc.scaled(data[i])) {
+			lasty = -1
+			continue
+		}
 		c := Cell{
 			Ch: lc.DotStyle,
-			Fg: lc.LineColor,
+			Fg: color,
 			Bg: lc.Bg,
 		}
 		x := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
-		y := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - int((lc.Data[i]-lc.bottomValue)/lc.scale+0.5)
+		y := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - int((lc.scaled(data[i])-lc.bottomValue)/lc.scale+0.5)
 
 		if lasty != -1 && lasty != y {
 			u := 1 // direction
@@ -166,11 +265,91 @@ func (lc *LineChart) renderDot() Buffer {
 	return buf
 }
 
+// mergeCandles merges consecutive candles so there are at most n candles
+func mergeCandles(candles []Candle, n int) []Candle {
+	if n <= 0 || len(candles) <= n {
+		return candles
+	}
+
+	merged := make([]Candle, n)
+	for i := range merged {
+		from := i * len(candles) / n
+		to := (i + 1) * len(candles) / n
+		c := candles[from]
+		for _, next := range candles[from+1 : to] {
+			c.High = math.Max(c.High, next.High)
+			c.Low = math.Min(c.Low, next.Low)
+			c.Close = next.Close
+		}
+		merged[i] = c
+	}
+
+	return merged
+}
+
+// each cell is divided into an upper and a lower half,
+// the candle bodies are drawn with half blocks and the wicks with lines
+func (lc *LineChart) renderCandle() Buffer {
+	buf := NewBuffer()
+
+	// return: the half cell index of the value from the bottom of the chart
+	getHalf := func(d float64) int {
+		return int((lc.scaled(d)-lc.bottomValue)/(lc.scale/2) + 0.5)
+	}
+	for i, c := range lc.candles {
+		color := lc.UpColor
+		if c.Close < c.Open {
+			color = lc.DownColor
+		}
+
+		bodyLow := getHalf(math.Min(c.Open, c.Close))
+		bodyHigh := getHalf(math.Max(c.Open, c.Close))
+		if bodyHigh == bodyLow {
+			// NOTE: an unchanged candle has a body of one half cell
+			bodyHigh++
+		}
+		wickLow := getHalf(c.Low) / 2
+		wickHigh := (getHalf(c.High) - 1) / 2
+
+		x := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
+		for b := wickLow; b <= wickHigh || b <= (bodyHigh-1)/2; b++ {
+			lower := 2*b >= bodyLow && 2*b < bodyHigh
+			upper := 2*b+1 >= bodyLow && 2*b+1 < bodyHigh
+			ch := '│'
+			switch {
+			case lower && upper:
+				ch = '█'
+			case lower:
+				ch = '▄'
+			case upper:
+				ch = '▀'
+			}
+			y := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - b
+			buf.Set(x, y, Cell{Ch: ch, Fg: color, Bg: lc.Bg})
+		}
+	}
+
+	return buf
+}
+
 func (lc *LineChart) calcLabelX() {
 	lc.labelX = [][]rune{}
 
 	for i, l := 0, 0; i < len(lc.DataLabels) && l < lc.axisXWidth; i++ {
-		if lc.Mode == "dot" {
+		if lc.Mode == "candle" {
+			if l >= len(lc.candles) {
+				break
+			}
+
+			// NOTE: merged candles are labeled with the label of their first candle
+			label := lc.DataLabels[l*len(lc.DataLabels)/len(lc.candles)]
+			s := str2runes(label)
+			w := strWidth(label)
+			if l+w <= lc.axisXWidth {
+				lc.labelX = append(lc.labelX, s)
+			}
+			l += w + lc.axisXLabelGap
+		} else if lc.Mode == "dot" {
 			if l >= len(lc.DataLabels) {
 				break
 			}
@@ -213,11 +392,25 @@ func (lc *LineChart) calcLabelY() {
 	span := lc.topValue - lc.bottomValue
 	lc.scale = span / float64(lc.axisYHeight)
 
+	format := shortenFloatVal
+	if lc.YLabelFormat != nil {
+		format = lc.YLabelFormat
+	}
+	label := func(v float64) string {
+		switch lc.YScale {
+		case "log":
+			return format(math.Pow(10, v))
+		case "percent":
+			return fmt.Sprintf("%+.2f%%", v)
+		}
+		return format(v)
+	}
+
 	n := (1 + lc.axisYHeight) / (lc.axisYLabelGap + 1)
 	lc.labelY = make([][]rune, n)
 	maxLen := 0
 	for i := 0; i < n; i++ {
-		s := str2runes(shortenFloatVal(lc.bottomValue + float64(i)*span/float64(n)))
+		s := str2runes(label(lc.bottomValue + float64(i)*span/float64(n)))
 		if len(s) > maxLen {
 			maxLen = len(s)
 		}
@@ -227,39 +420,60 @@ func (lc *LineChart) calcLabelY() {
 	lc.labelYSpace = maxLen
 }
 
+// firstValue returns the first value of the data which isn't NaN after the transform
+func firstValue(data []float64, transform func(float64) float64) float64 {
+	for _, v := range data {
+		if v = transform(v); !math.IsNaN(v) {
+			return v
+		}
+	}
+	return 0
+}
+
 func (lc *LineChart) calcLayout() {
 	// set datalabels if it is not provided
+	series := lc.series()
 	if (lc.DataLabels == nil || len(lc.DataLabels) == 0) || lc.autoLabels {
 		lc.autoLabels = true
-		lc.DataLabels = make([]string, len(lc.Data))
-		for i := range lc.Data {
+		lc.DataLabels = make([]string, len(series[0]))
+		for i := range series[0] {
 			lc.DataLabels[i] = fmt.Sprint(i)
 		}
 	}
 
 	// lazy increase, to avoid y shaking frequently
 	// update bound Y when drawing is gonna overflow
-	lc.minY = lc.Data[0]
-	lc.maxY = lc.Data[0]
+	lc.calcScaleBase()
+	lc.minY = firstValue(series[0], lc.scaled)
+	lc.maxY = lc.minY
 
 	lc.bottomValue = lc.minY
 	lc.topValue = lc.maxY
 
-	// valid visible range
-	vrange := lc.innerArea.Dx()
-	if lc.Mode == "braille" {
-		vrange = 2 * lc.innerArea.Dx()
-	}
-	if vrange > len(lc.Data) {
-		vrange = len(lc.Data)
-	}
-
-	for _, v := range lc.Data[:vrange] {
-		if v > lc.maxY {
-			lc.maxY = v
+	for _, data := range series {
+		// valid visible range
+		vrange := lc.innerArea.Dx()
+		if lc.Mode == "braille" {
+			vrange = 2 * lc.innerArea.Dx()
+		} else if lc.Mode == "candle" {
+			// NOTE: the candles are merged to fit the chart so all of them are visible
+			vrange = len(data)
+		}
+		if vrange > len(data) {
+			vrange = len(data)
 		}
-		if v < lc.minY {
-			lc.minY = v
+
+		for _, v := range data[:vrange] {
+			v = lc.scaled(v)
+			if math.IsNaN(v) {
+				continue
+			}
+			if v > lc.maxY {
+				lc.maxY = v
+			}
+			if v < lc.minY {
+				lc.minY = v
+			}
 		}
 	}
 
@@ -277,6 +491,9 @@ func (lc *LineChart) calcLayout() {
 	lc.calcLabelY()
 
 	lc.axisXWidth = lc.innerArea.Dx() - 1 - lc.labelYSpace
+	if lc.Mode == "candle" {
+		lc.candles = mergeCandles(lc.Candles, lc.axisXWidth-1)
+	}
 	lc.calcLabelX()
 
 	lc.drawingX = lc.innerArea.Min.X + 1 + lc.labelYSpace
@@ -331,20 +548,74 @@ func (lc *LineChart) plotAxes() Buffer {
 	return buf
 }
 
+// PlotColumns returns the x offset of the plotted data from the left of the chart
+// and the number of plotted columns. It's only valid after the chart is drawn
+func (lc *LineChart) PlotColumns() (int, int) {
+	series := lc.series()
+	if len(series) == 0 {
+		return 0, 0
+	}
+
+	n := len(series[0])
+	switch lc.Mode {
+	case "candle":
+		n = len(lc.candles)
+	case "braille":
+		n = n / 2
+	}
+	if n > lc.axisXWidth {
+		n = lc.axisXWidth
+	}
+
+	return lc.labelYSpace + 1, n
+}
+
+// Column returns the index into the data, or the candles in candle mode, of the last value plotted
+// in the column, the value and its y position. The last of the MultiData series is used since it's
+// drawn on top. It's only valid after the chart is drawn
+func (lc *LineChart) Column(col int) (int, float64, int) {
+	bottom := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3
+	switch lc.Mode {
+	case "candle":
+		index := (col+1)*len(lc.Candles)/len(lc.candles) - 1
+		value := lc.candles[col].Close
+		return index, value, bottom - int((lc.scaled(value)-lc.bottomValue)/(lc.scale/2)+0.5)/2
+	}
+
+	series := lc.series()
+	data := series[len(series)-1]
+	if lc.Mode == "dot" {
+		value := data[col]
+		return col, value, bottom - int((lc.scaled(value)-lc.bottomValue)/lc.scale+0.5)
+	}
+
+	index := 2*col + 1
+	value := data[index]
+	return index, value, bottom - int((lc.scaled(value)-lc.bottomValue)/(lc.scale/4)+0.5)/4
+}
+
 // Buffer implements Bufferer interface.
 func (lc *LineChart) Buffer() Buffer {
 	buf := lc.Block.Buffer()
 
-	if lc.Data == nil || len(lc.Data) == 0 {
+	series := lc.series()
+	if len(series) == 0 || len(series[0]) == 0 {
 		return buf
 	}
 	lc.calcLayout()
 	buf.Merge(lc.plotAxes())
 
-	if lc.Mode == "dot" {
-		buf.Merge(lc.renderDot())
-	} else {
-		buf.Merge(lc.renderBraille())
+	if lc.Mode == "candle" {
+		buf.Merge(lc.renderCandle())
+		return buf
+	}
+
+	for i, data := range series {
+		if lc.Mode == "dot" {
+			buf.Merge(lc.renderDot(data, lc.seriesColor(i)))
+		} else {
+			buf.Merge(lc.renderBraille(data, lc.seriesColor(i)))
+		}
 	}
 
 	return buf
@@ -2,8 +2,12 @@ package humanize
 
 import (
 	"bytes"
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -53,3 +57,70 @@ func Commaf0(v float64) string {
 	p := message.NewPrinter(language.English)
 	return p.Sprintf("%.0f", v)
 }
+
+// Localef produces a string form of the given number with the digit grouping
+// and decimal separator of the locale, keeping all of its decimals.
+//
+// e.g. Localef(834142.32, language.German) -> 834.142,32
+func Localef(v float64, tag language.Tag) string {
+	decimals := 0
+	parts := strings.Split(strconv.FormatFloat(v, 'f', -1, 64), ".")
+	if len(parts) > 1 {
+		decimals = len(parts[1])
+	}
+
+	return LocalefN(v, decimals, tag)
+}
+
+// LocalefN produces a string form of the given number rounded to the number
+// of decimals, with the digit grouping and decimal separator of the locale.
+//
+// e.g. LocalefN(834142.326, 2, language.French) -> 834 142,33
+func LocalefN(v float64, decimals int, tag language.Tag) string {
+	p := message.NewPrinter(tag)
+	return p.Sprintf(fmt.Sprintf("%%.%df", decimals), v)
+}
+
+// compactUnits are the magnitude suffixes of the compact form, each a thousand times the previous
+var compactUnits = []string{"", "K", "M", "B", "T"}
+
+// Compactf produces a short string form of the given number with a magnitude
+// suffix and at most the given number of decimals.
+//
+// e.g. Compactf(1234000000000, 2) -> 1.23T, Compactf(456700000000, 2) -> 456.7B
+func Compactf(v float64, decimals int) string {
+	return LocaleCompactf(v, decimals, language.English)
+}
+
+// LocaleCompactf produces a short string form of the given number with a
+// magnitude suffix, at most the given number of decimals and the decimal
+// separator of the locale.
+//
+// e.g. LocaleCompactf(12300000, 2, language.German) -> 12,3M
+func LocaleCompactf(v float64, decimals int, tag language.Tag) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	scale := math.Pow(10, float64(decimals))
+	unit := 0
+	abs := math.Abs(v)
+	for unit < len(compactUnits)-1 && math.Round(abs*scale)/scale >= 1000 {
+		abs /= 1000
+		unit++
+	}
+	if v < 0 {
+		abs = -abs
+	}
+
+	s := LocalefN(abs, decimals, tag)
+	if decimals > 0 {
+		// NOTE: trim the trailing zeros and then the decimal separator if there are no decimals left
+		s = strings.TrimRight(s, "0")
+		if r, size := utf8.DecodeLastRuneInString(s); !unicode.IsDigit(r) {
+			s = s[:len(s)-size]
+		}
+	}
+
+	return s + compactUnits[unit]
+}
@@ -0,0 +1,61 @@
+package humanize
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestCommaf(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0"},
+		{834142.32, "834,142.32"},
+		{-1234567, "-1,234,567"},
+		{100, "100"},
+	}
+
+	for _, test := range tests {
+		if got := Commaf(test.in); got != test.want {
+			t.Errorf("Commaf(%v) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestCompactf(t *testing.T) {
+	tests := []struct {
+		in       float64
+		decimals int
+		want     string
+	}{
+		{1234000000000, 2, "1.23T"},
+		{456700000000, 2, "456.7B"},
+		{12300000, 2, "12.3M"},
+		{12300000, 0, "12M"},
+		{999, 2, "999"},
+		{999.996, 2, "1K"},
+		{999950, 1, "1M"},
+		{1500, 2, "1.5K"},
+		{100000, 2, "100K"},
+		{0, 2, "0"},
+		{-2500000, 1, "-2.5M"},
+		{1234e15, 2, "1,234,000T"},
+	}
+
+	for _, test := range tests {
+		if got := Compactf(test.in, test.decimals); got != test.want {
+			t.Errorf("Compactf(%v, %d) = %q, want %q", test.in, test.decimals, got, test.want)
+		}
+	}
+}
+
+func TestLocaleCompactf(t *testing.T) {
+	if got, want := LocaleCompactf(12300000, 2, language.German), "12,3M"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := LocaleCompactf(1000, 2, language.German), "1K"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,26 @@
+package humanize
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLocalef(t *testing.T) {
+	tests := []struct {
+		in   float64
+		tag  language.Tag
+		want string
+	}{
+		{1234.56, language.English, "1,234.56"},
+		{1234.56, language.German, "1.234,56"},
+		{0.00012, language.German, "0,00012"},
+		{-1234, language.German, "-1.234"},
+	}
+
+	for _, test := range tests {
+		if got := Localef(test.in, test.tag); got != test.want {
+			t.Errorf("Localef(%v, %s) = %q, want %q", test.in, test.tag, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package indicators
+
+import (
+	"math"
+)
+
+// The indicators return a value for each value of the data. The values before
+// the indicator has enough data are NaN.
+
+// nans returns a slice of n NaN values
+func nans(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.NaN()
+	}
+
+	return values
+}
+
+// SMA returns the simple moving average of the data over the period
+func SMA(data []float64, period int) []float64 {
+	sma := nans(len(data))
+	if period <= 0 {
+		return sma
+	}
+
+	var sum float64
+	for i, v := range data {
+		sum += v
+		if i >= period {
+			sum -= data[i-period]
+		}
+		if i >= period-1 {
+			sma[i] = sum / float64(period)
+		}
+	}
+
+	return sma
+}
+
+// EMA returns the exponential moving average of the data over the period.
+// It's seeded with the simple moving average of the first period values
+func EMA(data []float64, period int) []float64 {
+	ema := nans(len(data))
+	if period <= 0 || len(data) < period {
+		return ema
+	}
+
+	var sum float64
+	for _, v := range data[:period] {
+		sum += v
+	}
+	ema[period-1] = sum / float64(period)
+
+	k := 2 / float64(period+1)
+	for i := period; i < len(data); i++ {
+		ema[i] = (data[i]-ema[i-1])*k + ema[i-1]
+	}
+
+	return ema
+}
+
+// Bollinger returns the upper, middle and lower Bollinger bands of the data over the period.
+// The bands are k population standard deviations away from the simple moving average
+func Bollinger(data []float64, period int, k float64) ([]float64, []float64, []float64) {
+	upper := nans(len(data))
+	middle := SMA(data, period)
+	lower := nans(len(data))
+	for i := range data {
+		if math.IsNaN(middle[i]) {
+			continue
+		}
+
+		var variance float64
+		for _, v := range data[i-period+1 : i+1] {
+			variance += (v - middle[i]) * (v - middle[i])
+		}
+		sd := math.Sqrt(variance / float64(period))
+		upper[i] = middle[i] + k*sd
+		lower[i] = middle[i] - k*sd
+	}
+
+	return upper, middle, lower
+}
+
+// RSI returns the relative strength index of the data over the period using Wilder's smoothing
+func RSI(data []float64, period int) []float64 {
+	rsi := nans(len(data))
+	if period <= 0 || len(data) <= period {
+		return rsi
+	}
+
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := data[i] - data[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	rsi[period] = relativeStrengthIndex(gain, loss)
+
+	for i := period + 1; i < len(data); i++ {
+		change := data[i] - data[i-1]
+		var g, l float64
+		if change > 0 {
+			g = change
+		} else {
+			l = -change
+		}
+		gain = (gain*float64(period-1) + g) / float64(period)
+		loss = (loss*float64(period-1) + l) / float64(period)
+		rsi[i] = relativeStrengthIndex(gain, loss)
+	}
+
+	return rsi
+}
+
+// relativeStrengthIndex returns the index of the average gain and loss
+func relativeStrengthIndex(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+
+	return 100 - 100/(1+gain/loss)
+}
+
+// MACD returns the moving average convergence divergence line, its signal line and their histogram.
+// The line is the difference of the fast and slow exponential moving averages and the signal line
+// is the exponential moving average of the line over the signal period
+func MACD(data []float64, fast, slow, signal int) ([]float64, []float64, []float64) {
+	fastEMA := EMA(data, fast)
+	slowEMA := EMA(data, slow)
+	macd := nans(len(data))
+	start := -1
+	for i := range data {
+		if math.IsNaN(fastEMA[i]) || math.IsNaN(slowEMA[i]) {
+			continue
+		}
+		macd[i] = fastEMA[i] - slowEMA[i]
+		if start < 0 {
+			start = i
+		}
+	}
+
+	signalLine := nans(len(data))
+	histogram := nans(len(data))
+	if start < 0 {
+		return macd, signalLine, histogram
+	}
+
+	copy(signalLine[start:], EMA(macd[start:], signal))
+	for i := range data {
+		if !math.IsNaN(signalLine[i]) {
+			histogram[i] = macd[i] - signalLine[i]
+		}
+	}
+
+	return macd, signalLine, histogram
+}
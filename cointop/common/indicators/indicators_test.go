@@ -0,0 +1,155 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// nan is a placeholder for the values before an indicator has enough data
+var nan = math.NaN()
+
+func equal(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.IsNaN(want[i]) {
+			if !math.IsNaN(got[i]) {
+				return false
+			}
+			continue
+		}
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func ramp(n int) []float64 {
+	data := make([]float64, n)
+	for i := range data {
+		data[i] = float64(i)
+	}
+
+	return data
+}
+
+func TestSMA(t *testing.T) {
+	tests := []struct {
+		data   []float64
+		period int
+		want   []float64
+	}{
+		{[]float64{1, 2, 3, 4, 5, 6}, 3, []float64{nan, nan, 2, 3, 4, 5}},
+		{[]float64{2, 4, 6, 8}, 1, []float64{2, 4, 6, 8}},
+		{[]float64{2, 4, 6, 8}, 4, []float64{nan, nan, nan, 5}},
+		{[]float64{2, 4}, 3, []float64{nan, nan}},
+		{[]float64{2, 4}, 0, []float64{nan, nan}},
+		{nil, 3, []float64{}},
+	}
+
+	for _, tt := range tests {
+		if got := SMA(tt.data, tt.period); !equal(got, tt.want) {
+			t.Errorf("SMA(%v, %d) = %v, want %v", tt.data, tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestEMA(t *testing.T) {
+	tests := []struct {
+		data   []float64
+		period int
+		want   []float64
+	}{
+		// k = 2/(3+1) = 0.5, seeded with the average of 1, 2 and 3
+		{[]float64{1, 2, 3, 4, 5}, 3, []float64{nan, nan, 2, 3, 4}},
+		{[]float64{1, 2, 3, 7, 3}, 3, []float64{nan, nan, 2, 4.5, 3.75}},
+		{[]float64{5, 5, 5, 5}, 2, []float64{nan, 5, 5, 5}},
+		{[]float64{1, 2}, 3, []float64{nan, nan}},
+	}
+
+	for _, tt := range tests {
+		if got := EMA(tt.data, tt.period); !equal(got, tt.want) {
+			t.Errorf("EMA(%v, %d) = %v, want %v", tt.data, tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	// the population standard deviation of the data is 2 and the mean is 5
+	data := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	upper, middle, lower := Bollinger(data, 8, 2)
+	want := []float64{nan, nan, nan, nan, nan, nan, nan}
+	if !equal(upper, append(want, 9)) {
+		t.Errorf("upper = %v, want 9", upper)
+	}
+	if !equal(middle, append(want, 5)) {
+		t.Errorf("middle = %v, want 5", middle)
+	}
+	if !equal(lower, append(want, 1)) {
+		t.Errorf("lower = %v, want 1", lower)
+	}
+
+	upper, middle, lower = Bollinger([]float64{3, 3, 3}, 2, 2)
+	if !equal(upper, []float64{nan, 3, 3}) || !equal(middle, []float64{nan, 3, 3}) || !equal(lower, []float64{nan, 3, 3}) {
+		t.Errorf("got %v %v %v, want flat bands at 3", upper, middle, lower)
+	}
+}
+
+func TestRSI(t *testing.T) {
+	tests := []struct {
+		data   []float64
+		period int
+		want   []float64
+	}{
+		// average gain and loss of 0.5, then (0.5+1)/2 = 0.75 and 0.5/2 = 0.25
+		{[]float64{1, 2, 1, 2}, 2, []float64{nan, nan, 50, 75}},
+		{[]float64{1, 2, 3, 4}, 2, []float64{nan, nan, 100, 100}},
+		{[]float64{4, 3, 2, 1}, 2, []float64{nan, nan, 0, 0}},
+		{[]float64{4, 4, 4}, 2, []float64{nan, nan, 50}},
+		{[]float64{4, 4}, 2, []float64{nan, nan}},
+	}
+
+	for _, tt := range tests {
+		if got := RSI(tt.data, tt.period); !equal(got, tt.want) {
+			t.Errorf("RSI(%v, %d) = %v, want %v", tt.data, tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestRSIReference(t *testing.T) {
+	data := []float64{
+		44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.28,
+		46.00, 46.03, 46.41, 46.22, 45.64, 46.21, 46.25, 45.71, 46.45, 45.78,
+	}
+	want := []float64{63.05, 67.11, 67.38, 60.18, 65.60, 57.91}
+
+	rsi := RSI(data, 14)
+	for i, w := range want {
+		if got := rsi[14+i]; math.Abs(got-w) > 0.01 {
+			t.Errorf("RSI[%d] = %.2f, want %.2f", 14+i, got, w)
+		}
+	}
+}
+
+func TestMACD(t *testing.T) {
+	// the exponential moving average of a ramp lags it by (period-1)/2,
+	// so the line is (i-1) - (i-2) = 1 and the signal line is 1
+	macd, signal, histogram := MACD(ramp(8), 3, 5, 2)
+	if want := []float64{nan, nan, nan, nan, 1, 1, 1, 1}; !equal(macd, want) {
+		t.Errorf("macd = %v, want %v", macd, want)
+	}
+	if want := []float64{nan, nan, nan, nan, nan, 1, 1, 1}; !equal(signal, want) {
+		t.Errorf("signal = %v, want %v", signal, want)
+	}
+	if want := []float64{nan, nan, nan, nan, nan, 0, 0, 0}; !equal(histogram, want) {
+		t.Errorf("histogram = %v, want %v", histogram, want)
+	}
+
+	macd, signal, histogram = MACD(ramp(3), 3, 5, 2)
+	if want := []float64{nan, nan, nan}; !equal(macd, want) || !equal(signal, want) || !equal(histogram, want) {
+		t.Errorf("got %v %v %v, want no values", macd, signal, histogram)
+	}
+}
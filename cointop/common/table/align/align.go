@@ -2,36 +2,77 @@ package align
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+
+	"github.com/mattn/go-runewidth"
 )
 
+// ansiEscape matches terminal color escape sequences
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Width returns the display width of the string, ignoring color escape sequences
+func Width(s string) int {
+	return runewidth.StringWidth(ansiEscape.ReplaceAllString(s, ""))
+}
+
+// Truncate truncates the string to the display width n, keeping color escape sequences intact
+func Truncate(s string, n int) string {
+	if Width(s) <= n {
+		return s
+	}
+
+	var b strings.Builder
+	w := 0
+	for len(s) > 0 {
+		if loc := ansiEscape.FindStringIndex(s); loc != nil && loc[0] == 0 {
+			b.WriteString(s[:loc[1]])
+			s = s[loc[1]:]
+			continue
+		}
+
+		r := []rune(s)[0]
+		rw := runewidth.RuneWidth(r)
+		if w+rw <= n {
+			b.WriteRune(r)
+			w += rw
+		}
+		s = s[len(string(r)):]
+	}
+
+	return b.String()
+}
+
 // AlignLeft align left
 func AlignLeft(s string, n int) string {
-	if len(s) > n {
-		return s[:n]
+	w := Width(s)
+	if w > n {
+		return Truncate(s, n)
 	}
 
-	return fmt.Sprintf("%s%s", s, strings.Repeat(" ", n-len(s)))
+	return fmt.Sprintf("%s%s", s, strings.Repeat(" ", n-w))
 }
 
 // AlignRight align right
 func AlignRight(s string, n int) string {
-	if len(s) > n {
-		return s[:n]
+	w := Width(s)
+	if w > n {
+		return Truncate(s, n)
 	}
 
-	return fmt.Sprintf("%s%s", strings.Repeat(" ", n-len(s)), s)
+	return fmt.Sprintf("%s%s", strings.Repeat(" ", n-w), s)
 }
 
 // AlignCenter align center
 func AlignCenter(s string, n int) string {
-	if len(s) > n {
-		return s[:n]
+	w := Width(s)
+	if w > n {
+		return Truncate(s, n)
 	}
 
-	pad := (n - len(s)) / 2
+	pad := (n - w) / 2
 	lpad := pad
-	rpad := n - len(s) - lpad
+	rpad := n - w - lpad
 
 	return fmt.Sprintf("%s%s%s", strings.Repeat(" ", lpad), s, strings.Repeat(" ", rpad))
 }
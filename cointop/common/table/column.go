@@ -11,6 +11,7 @@ type Col struct {
 	formatFn     FormatFn
 	align        Align
 	width        int
+	fixedWidth   int
 	perc         float32
 	minWidth     int
 	minWidthPerc int
@@ -55,12 +56,24 @@ func (c *Col) AlignCenter() *Col {
 	return c
 }
 
+// SetAlign sets alignment
+func (c *Col) SetAlign(a Align) *Col {
+	c.align = a
+	return c
+}
+
 // SetWidth set width
 func (c *Col) SetWidth(w int) *Col {
 	c.minWidth = w
 	return c
 }
 
+// SetFixedWidth sets a fixed content width which values are padded or truncated to
+func (c *Col) SetFixedWidth(w int) *Col {
+	c.fixedWidth = w
+	return c
+}
+
 // SetWidthPerc  set width percentage
 func (c *Col) SetWidthPerc(w int) *Col {
 	c.minWidthPerc = w
@@ -110,11 +110,18 @@ func (t *Table) normalizeColWidthPerc() {
 
 // Format format table
 func (t *Table) Format() *Table {
+	if len(t.cols) == 0 {
+		return t
+	}
+
 	for _, c := range t.cols {
-		c.width = len(c.name) + 1
+		c.width = align.Width(c.name) + 1
 		if c.minWidth > c.width {
 			c.width = c.minWidth
 		}
+		if c.fixedWidth > 0 {
+			c.width = c.fixedWidth + 1
+		}
 	}
 
 	for _, r := range t.rows {
@@ -125,16 +132,24 @@ func (t *Table) Format() *Table {
 				continue
 			}
 
+			var s string
 			if c.formatFn != nil {
-				r.strValues[j] = fmt.Sprintf("%s", c.formatFn(v)) + " "
+				s = fmt.Sprintf("%s", c.formatFn(v))
 			} else if c.format != "" {
-				r.strValues[j] = fmt.Sprintf(c.format, v) + " "
+				s = fmt.Sprintf(c.format, v)
 			} else {
-				r.strValues[j] = fmt.Sprintf("%v", v) + " "
+				s = fmt.Sprintf("%v", v)
 			}
 
-			if len(r.strValues[j]) > t.cols[j].width {
-				t.cols[j].width = len(r.strValues[j])
+			if c.fixedWidth > 0 {
+				r.strValues[j] = align.Truncate(s, c.fixedWidth) + " "
+				continue
+			}
+
+			r.strValues[j] = s + " "
+
+			if w := align.Width(r.strValues[j]); w > t.cols[j].width {
+				t.cols[j].width = w
 			}
 		}
 	}
@@ -0,0 +1,171 @@
+package cointop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cdyfng/coind/cointop/common/gizak/termui"
+)
+
+// maxCompareCoins is the maximum number of coins in the compare chart
+const maxCompareCoins = 6
+
+// ToggleCompareCoin marks or unmarks the highlighted coin for the compare chart
+func (ct *Cointop) ToggleCompareCoin() error {
+	ct.debuglog("ToggleCompareCoin()")
+	coin := ct.HighlightedRowCoin()
+	if coin == nil {
+		return nil
+	}
+
+	if i := ct.compareCoinIndex(coin); i >= 0 {
+		ct.State.compareCoins = append(ct.State.compareCoins[:i], ct.State.compareCoins[i+1:]...)
+	} else {
+		if len(ct.State.compareCoins) >= maxCompareCoins {
+			ct.UpdateStatusbar(fmt.Sprintf("Compare: up to %d coins can be compared", maxCompareCoins))
+			return nil
+		}
+		ct.State.compareCoins = append(ct.State.compareCoins, coin.Name)
+	}
+
+	go ct.UpdateTable()
+	if ct.State.compareChartVisible {
+		go ct.UpdateChart()
+	}
+
+	return nil
+}
+
+// ClearCompareCoins unmarks all the coins of the compare chart
+func (ct *Cointop) ClearCompareCoins() error {
+	ct.debuglog("ClearCompareCoins()")
+	ct.State.compareCoins = nil
+
+	go ct.UpdateTable()
+	if ct.State.compareChartVisible {
+		go ct.UpdateChart()
+	}
+
+	return nil
+}
+
+// ToggleCompareChart toggles between the compare chart and the coin chart
+func (ct *Cointop) ToggleCompareChart() error {
+	ct.debuglog("ToggleCompareChart()")
+	ct.State.compareChartVisible = !ct.State.compareChartVisible
+
+	go func() {
+		// keep these two synchronous to avoid race conditions
+		ct.ShowChartLoader()
+		ct.UpdateChart()
+	}()
+
+	return nil
+}
+
+// compareCoinIndex returns the series index of the coin in the compare chart or -1 if it's not marked
+func (ct *Cointop) compareCoinIndex(coin *Coin) int {
+	for i, name := range ct.State.compareCoins {
+		if name == coin.Name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// CompareChart renders the marked coins as percent change from the start of the chart range
+func (ct *Cointop) CompareChart() error {
+	ct.debuglog("CompareChart()")
+	chartPointsLock.Lock()
+	defer chartPointsLock.Unlock()
+
+	// TODO: not do this (SoC)
+	go ct.updateMarketbar()
+
+	chart := termui.NewLineChart()
+	chart.Height = ct.chartHeight()
+	chart.Border = false
+	chart.AxesColor = termui.ColorDefault
+
+	// NOTE: empty list means don't show x-axis labels
+	chart.DataLabels = []string{""}
+
+	var series [][]float64
+	var colors []ISprintf
+	var legend []string
+	for i, name := range ct.State.compareCoins {
+		icoin, _ := ct.State.allCoinsSlugMap.Load(name)
+		coin, ok := icoin.(*Coin)
+		if !ok {
+			continue
+		}
+
+		data, err := ct.chartData(coin.Symbol, coin.Name)
+		if err != nil || len(data) == 0 || data[0] == 0 {
+			continue
+		}
+
+		data = percentChangeSeries(data)
+		color := ct.colorscheme.ChartSeriesSprintf(i)
+		series = append(series, data)
+		colors = append(colors, color)
+		legend = append(legend, color(fmt.Sprintf("%s %+.2f%%", coin.Symbol, data[len(data)-1])))
+	}
+
+	if len(series) == 0 {
+		ct.State.chartPoints = nil
+		return nil
+	}
+
+	// NOTE: the series are plotted by index so they're stretched to the same length
+	n := 0
+	for _, data := range series {
+		if len(data) > n {
+			n = len(data)
+		}
+	}
+	for i := range series {
+		series[i] = resampleSeries(series[i], n)
+		// NOTE: the line color is the index into the chart colors plus one
+		chart.LineColors = append(chart.LineColors, termui.Attribute(i+1))
+	}
+
+	chart.MultiData = series
+	ct.State.chartPoints = ct.chartCells(chart)
+	ct.State.chartColors = colors
+	ct.State.chartLegend = fmt.Sprintf("%s %s", ct.colorscheme.Chart(fmt.Sprintf(" %s:", ct.State.selectedChartRange)), strings.Join(legend, ct.colorscheme.Chart("  ")))
+
+	return nil
+}
+
+// percentChangeSeries returns the percent change of each value from the first value
+func percentChangeSeries(data []float64) []float64 {
+	changes := make([]float64, len(data))
+	for i, v := range data {
+		changes[i] = (v/data[0] - 1) * 100
+	}
+
+	return changes
+}
+
+// resampleSeries linearly interpolates the series to n values
+func resampleSeries(data []float64, n int) []float64 {
+	if len(data) == n || len(data) < 2 {
+		return data
+	}
+
+	resampled := make([]float64, n)
+	step := float64(len(data)-1) / float64(n-1)
+	for i := range resampled {
+		x := float64(i) * step
+		j := int(x)
+		if j >= len(data)-1 {
+			resampled[i] = data[len(data)-1]
+			continue
+		}
+		resampled[i] = data[j] + (data[j+1]-data[j])*(x-float64(j))
+	}
+
+	return resampled
+}
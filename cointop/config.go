@@ -6,24 +6,33 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"golang.org/x/text/language"
 )
 
 var fileperm = os.FileMode(0644)
 
 type config struct {
-	Shortcuts     map[string]interface{}   `toml:"shortcuts"`
-	Favorites     map[string][]interface{} `toml:"favorites"`
-	Portfolio     map[string]interface{}   `toml:"portfolio"`
-	Currency      interface{}              `toml:"currency"`
-	DefaultView   interface{}              `toml:"default_view"`
-	CoinMarketCap map[string]interface{}   `toml:"coinmarketcap"`
-	API           interface{}              `toml:"api"`
-	Colorscheme   interface{}              `toml:"colorscheme"`
-	RefreshRate   interface{}              `toml:"refresh_rate"`
+	Shortcuts         map[string]interface{}   `toml:"shortcuts"`
+	Favorites         map[string][]interface{} `toml:"favorites"`
+	Portfolio         map[string]interface{}   `toml:"portfolio"`
+	Currency          interface{}              `toml:"currency"`
+	SecondaryCurrency interface{}              `toml:"secondary_currency"`
+	Locale            interface{}              `toml:"locale"`
+	DefaultView       interface{}              `toml:"default_view"`
+	CoinMarketCap     map[string]interface{}   `toml:"coinmarketcap"`
+	API               interface{}              `toml:"api"`
+	Colorscheme       interface{}              `toml:"colorscheme"`
+	RefreshRate       interface{}              `toml:"refresh_rate"`
+	Table             map[string]interface{}   `toml:"table"`
+	Filters           map[string]interface{}   `toml:"filters"`
+	Watchlists        map[string][]interface{} `toml:"watchlists"`
+	Chart             map[string]interface{}   `toml:"chart"`
+	TopMovers         map[string]interface{}   `toml:"top_movers"`
 }
 
 func (ct *Cointop) setupConfig() error {
@@ -46,6 +55,12 @@ func (ct *Cointop) setupConfig() error {
 	if err := ct.loadCurrencyFromConfig(); err != nil {
 		return err
 	}
+	if err := ct.loadSecondaryCurrencyFromConfig(); err != nil {
+		return err
+	}
+	if err := ct.loadLocaleFromConfig(); err != nil {
+		return err
+	}
 	if err := ct.loadDefaultViewFromConfig(); err != nil {
 		return err
 	}
@@ -61,6 +76,21 @@ func (ct *Cointop) setupConfig() error {
 	if err := ct.loadRefreshRateFromConfig(); err != nil {
 		return err
 	}
+	if err := ct.loadTableColumnsFromConfig(); err != nil {
+		return err
+	}
+	if err := ct.loadFiltersFromConfig(); err != nil {
+		return err
+	}
+	if err := ct.loadWatchlistsFromConfig(); err != nil {
+		return err
+	}
+	if err := ct.loadChartFromConfig(); err != nil {
+		return err
+	}
+	if err := ct.loadTopMoversFromConfig(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -169,6 +199,13 @@ func (ct *Cointop) configToToml() ([]byte, error) {
 		var i interface{} = v
 		shortcutsIfcs[k] = i
 	}
+	for scope, shortcuts := range ct.State.scopedShortcutKeys {
+		scoped := map[string]interface{}{}
+		for k, v := range shortcuts {
+			scoped[k] = v
+		}
+		shortcutsIfcs[scope] = scoped
+	}
 
 	var favorites []interface{}
 	for k, ok := range ct.State.favorites {
@@ -195,6 +232,11 @@ func (ct *Cointop) configToToml() ([]byte, error) {
 	}
 
 	var currencyIfc interface{} = ct.State.currencyConversion
+	var secondaryCurrencyIfc interface{} = ct.State.secondaryCurrencyConversion
+	var localeIfc interface{} = ""
+	if ct.State.locale != language.Und {
+		localeIfc = ct.State.locale.String()
+	}
 	var defaultViewIfc interface{} = ct.State.defaultView
 	var colorschemeIfc interface{} = ct.colorschemeName
 	var refreshRateIfc interface{} = uint(ct.State.refreshRate.Seconds())
@@ -204,16 +246,68 @@ func (ct *Cointop) configToToml() ([]byte, error) {
 	}
 	var apiChoiceIfc interface{} = ct.apiChoice
 
+	filtersIfc := map[string]interface{}{}
+	for name, expr := range ct.State.filters {
+		var i interface{} = expr
+		filtersIfc[name] = i
+	}
+
+	watchlistsIfc := map[string][]interface{}{}
+	for name, list := range ct.State.watchlists {
+		coins := []interface{}{}
+		for k, ok := range list {
+			if ok {
+				var i interface{} = k
+				coins = append(coins, i)
+			}
+		}
+		watchlistsIfc[name] = coins
+	}
+
+	var overlaysIfc []interface{}
+	for _, name := range chartOverlays {
+		if ct.State.chartOverlays[name] {
+			overlaysIfc = append(overlaysIfc, name)
+		}
+	}
+	settings := ct.State.indicatorSettings
+	chartIfc := map[string]interface{}{
+		"volume_height":        ct.State.volumeHeight,
+		"scale":                ct.State.chartScale,
+		"overlays":             overlaysIfc,
+		"indicator_panel":      ct.State.indicatorPanel,
+		"sma_period":           settings.SMAPeriod,
+		"ema_period":           settings.EMAPeriod,
+		"bollinger_period":     settings.BollingerPeriod,
+		"bollinger_deviations": settings.BollingerDeviations,
+		"rsi_period":           settings.RSIPeriod,
+		"macd_fast":            settings.MACDFast,
+		"macd_slow":            settings.MACDSlow,
+		"macd_signal":          settings.MACDSignal,
+	}
+
+	topMoversIfc := map[string]interface{}{
+		"min_market_cap": ct.State.topMoversMinMarketCap,
+		"min_volume":     ct.State.topMoversMinVolume,
+	}
+
 	var inputs = &config{
-		API:           apiChoiceIfc,
-		Colorscheme:   colorschemeIfc,
-		CoinMarketCap: cmcIfc,
-		Currency:      currencyIfc,
-		DefaultView:   defaultViewIfc,
-		Favorites:     favoritesIfcs,
-		RefreshRate:   refreshRateIfc,
-		Shortcuts:     shortcutsIfcs,
-		Portfolio:     portfolioIfc,
+		API:               apiChoiceIfc,
+		Colorscheme:       colorschemeIfc,
+		CoinMarketCap:     cmcIfc,
+		Currency:          currencyIfc,
+		SecondaryCurrency: secondaryCurrencyIfc,
+		Locale:            localeIfc,
+		DefaultView:       defaultViewIfc,
+		Favorites:         favoritesIfcs,
+		RefreshRate:       refreshRateIfc,
+		Shortcuts:         shortcutsIfcs,
+		Portfolio:         portfolioIfc,
+		Table:             ct.tableColumnsConfig(),
+		Filters:           filtersIfc,
+		Watchlists:        watchlistsIfc,
+		Chart:             chartIfc,
+		TopMovers:         topMoversIfc,
 	}
 
 	var b bytes.Buffer
@@ -228,17 +322,48 @@ func (ct *Cointop) configToToml() ([]byte, error) {
 
 func (ct *Cointop) loadShortcutsFromConfig() error {
 	ct.debuglog("loadShortcutsFromConfig()")
+	// NOTE: invalid shortcuts are reported along with the key conflicts instead of failing to start
+	ct.State.shortcutWarnings = nil
+	warn := func(format string, a ...interface{}) {
+		warning := fmt.Sprintf(format, a...)
+		ct.debuglog(fmt.Sprintf("shortcut warning: %s", warning))
+		ct.State.shortcutWarnings = append(ct.State.shortcutWarnings, warning)
+	}
+	valid := func(scope, key string, ifc interface{}) (string, bool) {
+		action, ok := ifc.(string)
+		if !ok || !ct.ActionExists(action) {
+			warn("%q in %s is bound to an unknown action %v", key, scope, ifc)
+			return "", false
+		}
+		if _, err := ct.parseKeySequence(key); err != nil {
+			warn("%q (%s) in %s is an invalid key: %v", key, action, scope, err)
+			return "", false
+		}
+		return action, true
+	}
+
 	for k, ifc := range ct.config.Shortcuts {
-		if v, ok := ifc.(string); ok {
-			if !ct.ActionExists(v) {
+		switch v := ifc.(type) {
+		case map[string]interface{}:
+			// NOTE: scoped shortcuts are in sections such as [shortcuts.chart]
+			if _, ok := shortcutScopes[k]; !ok {
+				warn("[shortcuts.%s] is an unknown shortcuts scope", k)
 				continue
 			}
-			if ct.State.shortcutKeys[k] == "" {
-				continue
+			shortcuts := make(map[string]string)
+			for key, ifc := range v {
+				if action, ok := valid(k, key, ifc); ok {
+					shortcuts[key] = action
+				}
+			}
+			ct.State.scopedShortcutKeys[k] = shortcuts
+		default:
+			if action, ok := valid("global", k, ifc); ok {
+				ct.State.shortcutKeys[k] = action
 			}
-			ct.State.shortcutKeys[k] = v
 		}
 	}
+	sort.Strings(ct.State.shortcutWarnings)
 	return nil
 }
 
@@ -250,6 +375,30 @@ func (ct *Cointop) loadCurrencyFromConfig() error {
 	return nil
 }
 
+func (ct *Cointop) loadSecondaryCurrencyFromConfig() error {
+	ct.debuglog("loadSecondaryCurrencyFromConfig()")
+	if currency, ok := ct.config.SecondaryCurrency.(string); ok {
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		if currency == ct.State.currencyConversion {
+			currency = ""
+		}
+		ct.State.secondaryCurrencyConversion = currency
+	}
+	return nil
+}
+
+func (ct *Cointop) loadLocaleFromConfig() error {
+	ct.debuglog("loadLocaleFromConfig()")
+	if locale, ok := ct.config.Locale.(string); ok {
+		tag, err := parseLocale(locale)
+		if err != nil {
+			return err
+		}
+		ct.State.locale = tag
+	}
+	return nil
+}
+
 func (ct *Cointop) loadDefaultViewFromConfig() error {
 	ct.debuglog("loadDefaultViewFromConfig()")
 	if defaultView, ok := ct.config.DefaultView.(string); ok {
@@ -302,33 +451,65 @@ func (ct *Cointop) loadRefreshRateFromConfig() error {
 
 func (ct *Cointop) getColorschemeColors() (map[string]interface{}, error) {
 	ct.debuglog("getColorschemeColors()")
-	var colors map[string]interface{}
 	if ct.colorschemeName == "" {
 		ct.colorschemeName = defaultColorscheme
+		var colors map[string]interface{}
 		if _, err := toml.Decode(DefaultColors, &colors); err != nil {
 			return nil, err
 		}
-	} else {
-		path := NormalizePath(fmt.Sprintf("~/.cointop/colors/%s.toml", ct.colorschemeName))
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			// NOTE: case for when cointop is set as the theme but the colorscheme file doesn't exist
-			if ct.colorschemeName == "cointop" {
-				if _, err := toml.Decode(DefaultColors, &colors); err != nil {
-					return nil, err
-				}
 
-				return colors, nil
+		return colors, nil
+	}
+
+	return ct.colorschemeColors(ct.colorschemeName)
+}
+
+// colorschemeColors returns the colors of the installed colorscheme, including the colors of the
+// colorscheme it extends that it doesn't override
+func (ct *Cointop) colorschemeColors(name string) (map[string]interface{}, error) {
+	ct.debuglog("colorschemeColors()")
+	return ct.extendedColorschemeColors(name, map[string]bool{})
+}
+
+func (ct *Cointop) extendedColorschemeColors(name string, seen map[string]bool) (map[string]interface{}, error) {
+	if seen[name] {
+		return nil, fmt.Errorf("colorscheme %q extends itself", name)
+	}
+	seen[name] = true
+
+	var colors map[string]interface{}
+	path := colorschemePath(name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// NOTE: case for when cointop is set as the theme but the colorscheme file doesn't exist
+		if name == defaultColorscheme {
+			if _, err := toml.Decode(DefaultColors, &colors); err != nil {
+				return nil, err
 			}
 
-			return nil, fmt.Errorf("The colorscheme file %q was not found.\n\nTo install standard themes, do:\n\ngit clone git@github.com:cointop-sh/colors.git ~/.cointop/colors\n\nFor additional instructions, visit: https://github.com/cointop-sh/colors", path)
+			return colors, nil
 		}
 
-		if _, err := toml.DecodeFile(path, &colors); err != nil {
-			return nil, err
-		}
+		return nil, fmt.Errorf("The colorscheme file %q was not found.\n\nTo install standard themes, do:\n\ngit clone git@github.com:cointop-sh/colors.git ~/.cointop/colors\n\nFor additional instructions, visit: https://github.com/cointop-sh/colors", path)
 	}
 
-	return colors, nil
+	if _, err := toml.DecodeFile(path, &colors); err != nil {
+		return nil, err
+	}
+
+	parent, ok := colors["extends"].(string)
+	if !ok {
+		return colors, nil
+	}
+
+	extended, err := ct.extendedColorschemeColors(parent, seen)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range colors {
+		extended[k] = v
+	}
+
+	return extended, nil
 }
 
 func (ct *Cointop) loadAPIChoiceFromConfig() error {
@@ -380,3 +561,258 @@ func (ct *Cointop) loadPortfolioFromConfig() error {
 
 	return nil
 }
+
+func (ct *Cointop) loadTableColumnsFromConfig() error {
+	ct.debuglog("loadTableColumnsFromConfig()")
+	loadView := func(view string, settings map[string]interface{}) error {
+		if ifcs, ok := settings["columns"].([]interface{}); ok {
+			var cols []string
+			for _, ifc := range ifcs {
+				col, _ := ifc.(string)
+				col = strings.ToLower(strings.TrimSpace(col))
+				if !isTableColumn(col) {
+					return fmt.Errorf("invalid table column %q", fmt.Sprint(ifc))
+				}
+				cols = append(cols, col)
+			}
+			ct.State.tableColumns[view] = cols
+		}
+		if widthsIfc, ok := settings["widths"].(map[string]interface{}); ok {
+			widths := make(map[string]int)
+			for col, ifc := range widthsIfc {
+				if w, ok := ifc.(int64); ok {
+					widths[strings.ToLower(col)] = clampTableColumnWidth(int(w))
+				}
+			}
+			ct.State.tableColumnWidths[view] = widths
+		}
+		if formatsIfc, ok := settings["formats"].(map[string]interface{}); ok {
+			formats := make(map[string]string)
+			for col, ifc := range formatsIfc {
+				format, _ := ifc.(string)
+				format = strings.ToLower(strings.TrimSpace(format))
+				if !isTableColumnFormat(format) {
+					return fmt.Errorf("invalid table format %q for %s, must be one of %s", fmt.Sprint(ifc), col, strings.Join(tableColumnFormats, ", "))
+				}
+				formats[strings.ToLower(col)] = format
+			}
+			ct.State.tableColumnFormats[view] = formats
+		}
+		return nil
+	}
+
+	// NOTE: the top level table settings are for the coins view, except the priority which is for all views
+	if err := loadView("coins", ct.config.Table); err != nil {
+		return err
+	}
+	if ifcs, ok := ct.config.Table["priority"].([]interface{}); ok {
+		var priority []string
+		for _, ifc := range ifcs {
+			if col, ok := ifc.(string); ok {
+				priority = append(priority, strings.ToLower(strings.TrimSpace(col)))
+			}
+		}
+		ct.State.tableColumnPriority = priority
+	}
+	if precision, ok := ct.config.Table["compact_precision"].(int64); ok {
+		if precision < 0 || precision > maxCompactPrecision {
+			return fmt.Errorf("invalid table compact precision %d, must be between 0 and %d", precision, maxCompactPrecision)
+		}
+		ct.State.compactPrecision = int(precision)
+	}
+	for _, view := range tableViews {
+		if settings, ok := ct.config.Table[view].(map[string]interface{}); ok {
+			if err := loadView(view, settings); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// tableColumnsConfig returns the table column settings for the config file
+func (ct *Cointop) tableColumnsConfig() map[string]interface{} {
+	viewConfig := func(view string) map[string]interface{} {
+		settings := map[string]interface{}{}
+		if cols, ok := ct.State.tableColumns[view]; ok {
+			colsIfc := []interface{}{}
+			for _, col := range cols {
+				colsIfc = append(colsIfc, col)
+			}
+			settings["columns"] = colsIfc
+		}
+		if widths, ok := ct.State.tableColumnWidths[view]; ok && len(widths) > 0 {
+			widthsIfc := map[string]interface{}{}
+			for col, w := range widths {
+				widthsIfc[col] = w
+			}
+			settings["widths"] = widthsIfc
+		}
+		if formats, ok := ct.State.tableColumnFormats[view]; ok && len(formats) > 0 {
+			formatsIfc := map[string]interface{}{}
+			for col, format := range formats {
+				formatsIfc[col] = format
+			}
+			settings["formats"] = formatsIfc
+		}
+		return settings
+	}
+
+	tableIfc := viewConfig("coins")
+	if len(ct.State.tableColumnPriority) > 0 {
+		priorityIfc := []interface{}{}
+		for _, col := range ct.State.tableColumnPriority {
+			priorityIfc = append(priorityIfc, col)
+		}
+		tableIfc["priority"] = priorityIfc
+	}
+	if ct.State.compactPrecision != defaultCompactPrecision {
+		tableIfc["compact_precision"] = ct.State.compactPrecision
+	}
+	for _, view := range tableViews {
+		if view == "coins" {
+			continue
+		}
+		if settings := viewConfig(view); len(settings) > 0 {
+			tableIfc[view] = settings
+		}
+	}
+
+	return tableIfc
+}
+
+func (ct *Cointop) loadFiltersFromConfig() error {
+	ct.debuglog("loadFiltersFromConfig()")
+	for name, exprIfc := range ct.config.Filters {
+		expr, ok := exprIfc.(string)
+		if !ok {
+			return fmt.Errorf("invalid named filter %q", name)
+		}
+		ct.State.filters[name] = expr
+	}
+
+	// NOTE: the expressions are parsed once all are loaded since they may reference each other
+	for name, expr := range ct.State.filters {
+		if _, err := parseCoinFilter(expr, ct.State.filters); err != nil {
+			return fmt.Errorf("invalid named filter %q: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (ct *Cointop) loadWatchlistsFromConfig() error {
+	ct.debuglog("loadWatchlistsFromConfig()")
+	for name, arr := range ct.config.Watchlists {
+		list := ct.watchlist(name)
+		if list == nil {
+			list = make(map[string]bool)
+		}
+		for _, ifc := range arr {
+			v, ok := ifc.(string)
+			if !ok {
+				return fmt.Errorf("invalid watchlist %q", name)
+			}
+			list[v] = true
+		}
+		// NOTE: the favorites are the default list, which is kept in the favorites section
+		if name != defaultWatchlist {
+			ct.State.watchlists[name] = list
+		}
+	}
+
+	return nil
+}
+
+func (ct *Cointop) loadChartFromConfig() error {
+	ct.debuglog("loadChartFromConfig()")
+	if volumeHeight, ok := ct.config.Chart["volume_height"].(int64); ok {
+		if volumeHeight < 0 || volumeHeight > maxVolumeHeight {
+			return fmt.Errorf("invalid chart volume height %d, must be between 0 and %d", volumeHeight, maxVolumeHeight)
+		}
+		ct.State.volumeHeight = int(volumeHeight)
+	}
+
+	if scale, ok := ct.config.Chart["scale"].(string); ok {
+		if !ct.isChartScale(scale) {
+			return fmt.Errorf("invalid chart scale %q, must be one of %s", scale, strings.Join(chartScales, ", "))
+		}
+		ct.State.chartScale = scale
+	}
+
+	if ifcs, ok := ct.config.Chart["overlays"].([]interface{}); ok {
+		for _, ifc := range ifcs {
+			name, ok := ifc.(string)
+			if !ok || !ct.isChartOverlay(name) {
+				return fmt.Errorf("invalid chart overlay %v, must be one of %s", ifc, strings.Join(chartOverlays, ", "))
+			}
+			ct.State.chartOverlays[name] = true
+		}
+	}
+
+	if panel, ok := ct.config.Chart["indicator_panel"].(string); ok {
+		if panel != "" && !ct.isIndicatorPanel(panel) {
+			return fmt.Errorf("invalid chart indicator panel %q, must be one of %s", panel, strings.Join(indicatorPanels, ", "))
+		}
+		ct.State.indicatorPanel = panel
+	}
+
+	periods := map[string]*int{
+		"sma_period":       &ct.State.indicatorSettings.SMAPeriod,
+		"ema_period":       &ct.State.indicatorSettings.EMAPeriod,
+		"bollinger_period": &ct.State.indicatorSettings.BollingerPeriod,
+		"rsi_period":       &ct.State.indicatorSettings.RSIPeriod,
+		"macd_fast":        &ct.State.indicatorSettings.MACDFast,
+		"macd_slow":        &ct.State.indicatorSettings.MACDSlow,
+		"macd_signal":      &ct.State.indicatorSettings.MACDSignal,
+	}
+	for key, period := range periods {
+		if value, ok := ct.config.Chart[key].(int64); ok {
+			if value < 1 {
+				return fmt.Errorf("invalid chart %s %d, must be at least 1", key, value)
+			}
+			*period = int(value)
+		}
+	}
+
+	switch deviations := ct.config.Chart["bollinger_deviations"].(type) {
+	case int64:
+		ct.State.indicatorSettings.BollingerDeviations = float64(deviations)
+	case float64:
+		ct.State.indicatorSettings.BollingerDeviations = deviations
+	}
+	if ct.State.indicatorSettings.BollingerDeviations <= 0 {
+		return fmt.Errorf("invalid chart bollinger_deviations %g, must be greater than 0", ct.State.indicatorSettings.BollingerDeviations)
+	}
+
+	if ct.State.indicatorSettings.MACDFast >= ct.State.indicatorSettings.MACDSlow {
+		return fmt.Errorf("invalid chart macd_fast %d, must be less than macd_slow %d", ct.State.indicatorSettings.MACDFast, ct.State.indicatorSettings.MACDSlow)
+	}
+
+	return nil
+}
+
+func (ct *Cointop) loadTopMoversFromConfig() error {
+	ct.debuglog("loadTopMoversFromConfig()")
+	for key, min := range map[string]*float64{
+		"min_market_cap": &ct.State.topMoversMinMarketCap,
+		"min_volume":     &ct.State.topMoversMinVolume,
+	} {
+		switch v := ct.config.TopMovers[key].(type) {
+		case nil:
+			continue
+		case int64:
+			*min = float64(v)
+		case float64:
+			*min = v
+		default:
+			return fmt.Errorf("invalid top movers %s %v, must be a number", key, v)
+		}
+		if *min < 0 {
+			return fmt.Errorf("invalid top movers %s %v, must not be negative", key, *min)
+		}
+	}
+
+	return nil
+}
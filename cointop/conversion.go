@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/cdyfng/coind/cointop/common/api/util"
 	color "github.com/cdyfng/coind/cointop/common/color"
+	"github.com/cdyfng/coind/cointop/common/filecache"
 	"github.com/cdyfng/coind/cointop/common/pad"
 )
 
@@ -136,8 +139,15 @@ func (ct *Cointop) sortedSupportedCurrencyConversions() []string {
 
 func (ct *Cointop) updateConvertMenu() {
 	ct.debuglog("updateConvertMenu()")
-	header := ct.colorscheme.MenuHeader(fmt.Sprintf(" Currency Conversion %s\n\n", pad.Left("[q] close menu ", ct.maxTableWidth-20, " ")))
-	helpline := " Press the corresponding key to select currency for conversion\n\n"
+	title := "Currency Conversion"
+	selected := ct.State.currencyConversion
+	helpline := " Press the corresponding key to select currency for conversion. Press [tab] to select the secondary currency\n\n"
+	if ct.State.convertMenuSecondary {
+		title = "Secondary Currency Conversion"
+		selected = ct.State.secondaryCurrencyConversion
+		helpline = " Press the corresponding key to select secondary currency, [-] to disable it. Press [tab] to select the primary currency\n\n"
+	}
+	header := ct.colorscheme.MenuHeader(fmt.Sprintf(" %s %s\n\n", title, pad.Left("[q] close menu ", ct.ClampedWidth()-len(title)-1, " ")))
 	cnt := 0
 	h := ct.Views.ConvertMenu.Height()
 	percol := h - 5
@@ -154,7 +164,7 @@ func (ct *Cointop) updateConvertMenu() {
 			cnt = 0
 		}
 		shortcut := string(alphanumericcharacters[i])
-		if key == ct.State.currencyConversion {
+		if key == selected {
 			shortcut = ct.colorscheme.MenuLabelActive(color.Bold("*"))
 			key = ct.colorscheme.Menu(color.Bold(key))
 			currency = ct.colorscheme.MenuLabelActive(color.Bold(currency))
@@ -201,6 +211,36 @@ func (ct *Cointop) setCurrencyConverstionFn(convert string) func() error {
 		}
 
 		ct.State.currencyConversion = convert
+		if ct.State.secondaryCurrencyConversion == convert {
+			ct.State.secondaryCurrencyConversion = ""
+		}
+
+		if err := ct.Save(); err != nil {
+			return err
+		}
+
+		go ct.refreshAll()
+		return nil
+	}
+}
+
+func (ct *Cointop) setSecondaryCurrencyConverstionFn(convert string) func() error {
+	ct.debuglog("setSecondaryCurrencyConverstionFn()")
+	return func() error {
+		ct.hideConvertMenu()
+
+		// NOTE: a secondary currency equal to the primary one is redundant
+		if convert == ct.State.currencyConversion {
+			convert = ""
+		}
+
+		// NOTE: return if the currency selection wasn't changed
+		if ct.State.secondaryCurrencyConversion == convert {
+			return nil
+		}
+
+		ct.State.secondaryCurrencyConversion = convert
+		ct.State.secondaryConversionRate = 0
 
 		if err := ct.Save(); err != nil {
 			return err
@@ -211,6 +251,117 @@ func (ct *Cointop) setCurrencyConverstionFn(convert string) func() error {
 	}
 }
 
+// setCurrencyMenuSelectionFn sets the primary or secondary currency depending on the convert menu mode
+func (ct *Cointop) setCurrencyMenuSelectionFn(convert string) func() error {
+	ct.debuglog("setCurrencyMenuSelectionFn()")
+	return func() error {
+		if ct.State.convertMenuSecondary {
+			return ct.setSecondaryCurrencyConverstionFn(convert)()
+		}
+
+		return ct.setCurrencyConverstionFn(convert)()
+	}
+}
+
+// disableSecondaryCurrency clears the secondary currency when the convert menu is in secondary mode
+func (ct *Cointop) disableSecondaryCurrency() error {
+	ct.debuglog("disableSecondaryCurrency()")
+	if !ct.State.convertMenuSecondary {
+		return nil
+	}
+
+	return ct.setSecondaryCurrencyConverstionFn("")()
+}
+
+// toggleConvertMenuSecondary switches the convert menu between the primary and secondary currency selection
+func (ct *Cointop) toggleConvertMenuSecondary() error {
+	ct.debuglog("toggleConvertMenuSecondary()")
+	ct.State.convertMenuSecondary = !ct.State.convertMenuSecondary
+	ct.updateConvertMenu()
+	return nil
+}
+
+// updateSecondaryConversionRate fetches the exchange rate from the primary to the secondary currency
+func (ct *Cointop) updateSecondaryConversionRate() error {
+	ct.debuglog("updateSecondaryConversionRate()")
+	from := ct.State.currencyConversion
+	to := ct.State.secondaryCurrencyConversion
+	if to == "" {
+		ct.State.secondaryConversionRate = 0
+		return nil
+	}
+
+	// NOTE: a rate of zero marks the secondary currency columns unavailable, since the rate of the
+	// previous currencies doesn't apply if they were changed
+	rate, err := ct.exchangeRate(from, to)
+	if err != nil {
+		ct.State.secondaryConversionRate = 0
+		return fmt.Errorf("no exchange rate from %s to %s: %v", from, to, err)
+	}
+
+	ct.State.secondaryConversionRate = rate
+	return nil
+}
+
+// exchangeRate returns the amount of the to currency for one unit of the from currency
+func (ct *Cointop) exchangeRate(from string, to string) (float64, error) {
+	ct.debuglog("exchangeRate()")
+	var rate float64
+	cachekey := ct.CacheKey(fmt.Sprintf("rate_%s_%s", from, to))
+	cached, found := ct.cache.Get(cachekey)
+	if found {
+		// cache hit
+		rate, _ = cached.(float64)
+		ct.debuglog("soft cache hit")
+	}
+
+	if rate == 0 {
+		var err error
+		rate, err = ct.api.GetExchangeRate(from, to)
+		if err != nil {
+			filecache.Get(cachekey, &rate)
+			if rate == 0 {
+				return 0, err
+			}
+		}
+
+		ct.cache.Set(cachekey, rate, 1*time.Minute)
+		go func() {
+			filecache.Set(cachekey, rate, 24*time.Hour)
+		}()
+	}
+
+	return rate, nil
+}
+
+// toSecondaryCurrency converts a value in the primary currency to the secondary currency
+func (ct *Cointop) toSecondaryCurrency(value float64) float64 {
+	if ct.State.secondaryCurrencyConversion == "" {
+		return 0
+	}
+
+	return value * ct.State.secondaryConversionRate
+}
+
+// toSecondaryPrice converts a price in the primary currency to the secondary currency
+func (ct *Cointop) toSecondaryPrice(price float64) float64 {
+	return util.FormatPrice(ct.toSecondaryCurrency(price), ct.State.secondaryCurrencyConversion)
+}
+
+// secondaryColumn returns the table column name for the secondary currency, or empty if not set
+func (ct *Cointop) secondaryColumn(name string) string {
+	if ct.State.secondaryCurrencyConversion == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s_%s", name, strings.ToLower(ct.State.secondaryCurrencyConversion))
+}
+
+// secondaryCurrencySymbol returns the symbol for the secondary currency
+func (ct *Cointop) secondaryCurrencySymbol() string {
+	return currencySymbol(ct.State.secondaryCurrencyConversion)
+}
+
 // currencySymbol returns the symbol for the currency
 func (ct *Cointop) currencySymbol() string {
 	ct.debuglog("currencySymbol()")
@@ -225,6 +376,7 @@ func (ct *Cointop) currencySymbol() string {
 func (ct *Cointop) showConvertMenu() error {
 	ct.debuglog("showConvertMenu()")
 	ct.State.convertMenuVisible = true
+	ct.State.convertMenuSecondary = false
 	ct.updateConvertMenu()
 	ct.SetActiveView(ct.Views.ConvertMenu.Name())
 	return nil
@@ -11,6 +11,46 @@ chart_fg = "white"
 chart_bg = "black"
 chart_bold = false
 
+chart_cursor_fg = "yellow"
+chart_cursor_bg = "black"
+chart_cursor_bold = false
+
+chart_volume_fg = "cyan"
+chart_volume_bg = "black"
+chart_volume_bold = false
+
+chart_series_1_fg = "cyan"
+chart_series_1_bg = "black"
+chart_series_1_bold = false
+
+chart_series_2_fg = "yellow"
+chart_series_2_bg = "black"
+chart_series_2_bold = false
+
+chart_series_3_fg = "magenta"
+chart_series_3_bg = "black"
+chart_series_3_bold = false
+
+chart_series_4_fg = "green"
+chart_series_4_bg = "black"
+chart_series_4_bold = false
+
+chart_series_5_fg = "red"
+chart_series_5_bg = "black"
+chart_series_5_bold = false
+
+chart_series_6_fg = "blue"
+chart_series_6_bg = "black"
+chart_series_6_bold = false
+
+chart_candle_up_fg = "green"
+chart_candle_up_bg = "black"
+chart_candle_up_bold = false
+
+chart_candle_down_fg = "red"
+chart_candle_down_bg = "black"
+chart_candle_down_bold = false
+
 marketbar_fg = "white"
 marketbar_bg = "black"
 marketbar_bold = false
@@ -59,6 +99,14 @@ table_column_change_up_fg = "green"
 table_column_change_up_bg = "black"
 table_column_change_up_bold = false
 
+table_row_flash_up_fg = "black"
+table_row_flash_up_bg = "green"
+table_row_flash_up_bold = false
+
+table_row_flash_down_fg = "black"
+table_row_flash_down_bg = "red"
+table_row_flash_down_bold = false
+
 table_header_fg = "black"
 table_header_bg = "green"
 table_header_bold = false
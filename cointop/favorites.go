@@ -2,35 +2,18 @@ package cointop
 
 import "sort"
 
+// toggleFavorite toggles the highlighted coin in the favorites. The watchlists menu adds the coin
+// to the other lists
 func (ct *Cointop) toggleFavorite() error {
 	ct.debuglog("toggleFavorite()")
-	coin := ct.HighlightedRowCoin()
-	if coin == nil {
-		return nil
-	}
-
-	_, ok := ct.State.favorites[coin.Name]
-	if ok {
-		delete(ct.State.favorites, coin.Name)
-		coin.Favorite = false
-	} else {
-		ct.State.favorites[coin.Name] = true
-		coin.Favorite = true
-	}
-
-	if err := ct.Save(); err != nil {
-		return err
-	}
-
-	go ct.UpdateTable()
-
-	return nil
+	return ct.toggleWatchlistCoin(defaultWatchlist, ct.HighlightedRowCoin())
 }
 
 func (ct *Cointop) toggleShowFavorites() error {
 	ct.debuglog("toggleShowFavorites()")
 	ct.State.portfolioVisible = false
 	ct.State.filterByFavorites = !ct.State.filterByFavorites
+	ct.State.selectedWatchlist = defaultWatchlist
 	go ct.UpdateTable()
 	return nil
 }
@@ -40,7 +23,7 @@ func (ct *Cointop) getFavoritesSlice() []*Coin {
 	sliced := []*Coin{}
 	for i := range ct.State.allCoins {
 		coin := ct.State.allCoins[i]
-		if coin.Favorite {
+		if ct.inWatchlist(ct.State.selectedWatchlist, coin) {
 			sliced = append(sliced, coin)
 		}
 	}
@@ -0,0 +1,223 @@
+package cointop
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cdyfng/coind/cointop/common/filter"
+)
+
+// FilterFieldView is structure for filter field view
+type FilterFieldView struct {
+	*View
+}
+
+// NewFilterFieldView returns a new filter field view
+func NewFilterFieldView() *FilterFieldView {
+	return &FilterFieldView{NewView("filterfield")}
+}
+
+// named filter definitions, e.g. @large = marketcap > 1e10
+var namedFilterDefinitionRegex = regexp.MustCompile(`^@([A-Za-z0-9_\-]+)\s*=([^=~].*|)$`)
+
+// coinFilterFields resolves the filter expression fields of a coin
+type coinFilterFields struct {
+	coin *Coin
+}
+
+// Field returns the value of the coin field. The field names match the table column names
+func (f coinFilterFields) Field(name string) (interface{}, bool) {
+	coin := f.coin
+	switch name {
+	case "id":
+		return coin.ID, true
+	case "rank":
+		return coin.Rank, true
+	case "name":
+		return coin.Name, true
+	case "symbol":
+		return coin.Symbol, true
+	case "price":
+		return coin.Price, true
+	case "marketcap":
+		return coin.MarketCap, true
+	case "24hvolume":
+		return coin.Volume24H, true
+	case "1hchange":
+		return coin.PercentChange1H, true
+	case "24hchange":
+		return coin.PercentChange24H, true
+	case "7dchange":
+		return coin.PercentChange7D, true
+	case "30dchange":
+		return coin.PercentChange30D, true
+	case "1ychange":
+		return coin.PercentChange1Y, true
+	case "totalsupply":
+		return coin.TotalSupply, true
+	case "availablesupply":
+		return coin.AvailableSupply, true
+	case "holdings":
+		return coin.Holdings, true
+	case "balance":
+		return coin.Balance, true
+	case "favorite":
+		return coin.Favorite, true
+	}
+
+	return nil, false
+}
+
+func (ct *Cointop) openFilter() error {
+	ct.debuglog("openFilter()")
+	ct.State.filterFieldVisible = true
+	ct.SetActiveView(ct.Views.FilterField.Name())
+	return nil
+}
+
+func (ct *Cointop) cancelFilter() error {
+	ct.debuglog("cancelFilter()")
+	ct.State.filterFieldVisible = false
+	ct.SetActiveView(ct.Views.Table.Name())
+	return nil
+}
+
+func (ct *Cointop) doFilter() error {
+	ct.debuglog("doFilter()")
+	q := strings.TrimSpace(ct.Views.FilterField.Backing().Buffer())
+	q = strings.TrimSpace(strings.TrimPrefix(q, "|"))
+
+	ct.State.filterFieldVisible = false
+	ct.SetActiveView(ct.Views.Table.Name())
+
+	ct.State.filterInput = q
+	if err := ct.applyFilterInput(q); err != nil {
+		ct.UpdateStatusbar(fmt.Sprintf("Filter error: %s", err))
+	}
+
+	return nil
+}
+
+// applyFilterInput applies the filter bar input, which is either an expression or a named filter definition
+func (ct *Cointop) applyFilterInput(q string) error {
+	ct.debuglog("applyFilterInput()")
+	if matches := namedFilterDefinitionRegex.FindStringSubmatch(q); len(matches) > 0 {
+		name := matches[1]
+		expr := strings.TrimSpace(matches[2])
+		if expr == "" {
+			return ct.deleteNamedFilter(name)
+		}
+		if err := ct.saveNamedFilter(name, expr); err != nil {
+			return err
+		}
+		q = "@" + name
+		ct.State.filterInput = q
+	}
+
+	return ct.setFilter(q)
+}
+
+// setFilter parses and applies the filter expression. An empty expression clears the filter
+func (ct *Cointop) setFilter(q string) error {
+	ct.debuglog("setFilter()")
+	if strings.TrimSpace(q) == "" {
+		return ct.clearFilter()
+	}
+
+	expr, err := ct.parseFilter(q)
+	if err != nil {
+		return err
+	}
+
+	ct.State.filter = expr
+	ct.State.page = 0
+	go ct.UpdateTable()
+	return nil
+}
+
+// parseFilter parses and validates the expression with the saved named filters
+func (ct *Cointop) parseFilter(q string) (*filter.Expr, error) {
+	ct.debuglog("parseFilter()")
+	return parseCoinFilter(q, ct.State.filters)
+}
+
+// parseCoinFilter parses the expression with the named filters and validates it against the coin fields
+func parseCoinFilter(q string, filters map[string]string) (*filter.Expr, error) {
+	expr, err := filter.ParseNamed(q, filters)
+	if err != nil {
+		return nil, err
+	}
+	if err := expr.Validate(coinFilterFields{&Coin{}}); err != nil {
+		return nil, err
+	}
+
+	return expr, nil
+}
+
+func (ct *Cointop) clearFilter() error {
+	ct.debuglog("clearFilter()")
+	if ct.State.filter == nil {
+		return nil
+	}
+
+	ct.State.filter = nil
+	ct.State.filterInput = ""
+	ct.State.page = 0
+	go ct.UpdateTable()
+	return nil
+}
+
+// saveNamedFilter validates the expression and saves it as a named filter in the config
+func (ct *Cointop) saveNamedFilter(name string, q string) error {
+	ct.debuglog("saveNamedFilter()")
+	// NOTE: the expression is parsed as if it was saved so references back to the name are caught
+	filters := map[string]string{name: q}
+	for k, v := range ct.State.filters {
+		if k != name {
+			filters[k] = v
+		}
+	}
+	if _, err := parseCoinFilter("@"+name, filters); err != nil {
+		return err
+	}
+
+	ct.State.filters[name] = q
+	return ct.Save()
+}
+
+// deleteNamedFilter deletes the named filter from the config
+func (ct *Cointop) deleteNamedFilter(name string) error {
+	ct.debuglog("deleteNamedFilter()")
+	if _, ok := ct.State.filters[name]; !ok {
+		return fmt.Errorf("unknown named filter @%s", name)
+	}
+
+	delete(ct.State.filters, name)
+	return ct.Save()
+}
+
+// filterCoins returns the coins matching the active filter
+func (ct *Cointop) filterCoins(coins []*Coin) []*Coin {
+	ct.debuglog("filterCoins()")
+	if ct.State.filter == nil {
+		return coins
+	}
+
+	var list []*Coin
+	for _, coin := range coins {
+		if coin == nil {
+			continue
+		}
+		ok, err := ct.State.filter.Match(coinFilterFields{coin})
+		if err != nil {
+			ct.debuglog(fmt.Sprintf("filter error: %s", err))
+			continue
+		}
+		if ok {
+			list = append(list, coin)
+		}
+	}
+
+	return list
+}
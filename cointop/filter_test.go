@@ -0,0 +1,42 @@
+package cointop
+
+import "testing"
+
+func TestParseCoinFilter(t *testing.T) {
+	filters := map[string]string{
+		"big":      "marketcap > 1e9",
+		"movers":   "24hchange > 5",
+		"bigmover": "@big && @movers",
+		"a":        "@b",
+		"b":        "@a",
+		"unknown":  "volume > 1",
+	}
+	coin := &Coin{Name: "foo@bar", Symbol: "FOO", MarketCap: 2e9, PercentChange24H: 10}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{`@bigmover`, true},
+		{`@big && !@movers`, false},
+		{`name == "foo@bar"`, true},
+		{`name =~ "@big"`, false},
+	}
+
+	for _, test := range tests {
+		expr, err := parseCoinFilter(test.input, filters)
+		if err != nil {
+			t.Errorf("parseCoinFilter(%q) error: %v", test.input, err)
+			continue
+		}
+		if got, _ := expr.Match(coinFilterFields{coin}); got != test.want {
+			t.Errorf("parseCoinFilter(%q) matched %v, want %v", test.input, got, test.want)
+		}
+	}
+
+	for _, input := range []string{"@a", "@unknown", "@missing", `name == "foo" && @`} {
+		if _, err := parseCoinFilter(input, filters); err == nil {
+			t.Errorf("parseCoinFilter(%q) expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cointop
+
+import (
+	"time"
+)
+
+// priceFlashDuration is how long the changed prices are highlighted after a refresh
+const priceFlashDuration = 2 * time.Second
+
+// trackPriceChange records the change of the coin price from the update. Prices fetched
+// in another currency aren't compared
+func trackPriceChange(coin *Coin, update *Coin) bool {
+	if coin.Currency != update.Currency || coin.Price == 0 || coin.Price == update.Price {
+		return false
+	}
+
+	coin.PriceChange = update.Price - coin.Price
+	coin.PriceChangedAt = time.Now()
+	return true
+}
+
+// refreshPriceChange returns the change of the coin price at the last refresh
+func (ct *Cointop) refreshPriceChange(coin *Coin) float64 {
+	if coin.PriceChangedAt.Before(ct.State.lastRefresh) {
+		return 0
+	}
+
+	return coin.PriceChange
+}
+
+// priceFlashColor returns the flash color of the coin price if it changed within the flash duration, or the color
+func (ct *Cointop) priceFlashColor(coin *Coin, color func(a ...interface{}) string) func(a ...interface{}) string {
+	if time.Since(coin.PriceChangedAt) > priceFlashDuration {
+		return color
+	}
+
+	switch change := ct.refreshPriceChange(coin); {
+	case change > 0:
+		return ct.colorscheme.TableRowFlashUp
+	case change < 0:
+		return ct.colorscheme.TableRowFlashDown
+	}
+
+	return color
+}
+
+// formatPriceChange returns the price change with its sign
+func (ct *Cointop) formatPriceChange(change float64) string {
+	if change > 0 {
+		return "+" + ct.formatNumber(change)
+	}
+
+	return ct.formatNumber(change)
+}
@@ -3,8 +3,10 @@ package cointop
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/cdyfng/coind/cointop/common/pad"
+	"github.com/miguelmota/gocui"
 )
 
 // HelpView is structure for help view
@@ -17,45 +19,130 @@ func NewHelpView() *HelpView {
 	return &HelpView{NewView("help")}
 }
 
+// helpEntry is an action listed in the help with its shortcut keys
+type helpEntry struct {
+	Action      string
+	Keys        []string
+	Category    string
+	Description string
+}
+
+// helpEntries returns the actions matching the query, ordered by category then by action name.
+// The query is matched against the action name, keys, category and description, ignoring case
+func (ct *Cointop) helpEntries(query string) []helpEntry {
+	keysByAction := make(map[string][]string)
+	for k, action := range ct.State.shortcutKeys {
+		if k == "" {
+			continue
+		}
+		action = strings.ToLower(action)
+		keysByAction[action] = append(keysByAction[action], k)
+	}
+	for scope, shortcuts := range ct.State.scopedShortcutKeys {
+		for k, action := range shortcuts {
+			action = strings.ToLower(action)
+			keysByAction[action] = append(keysByAction[action], fmt.Sprintf("%s:%s", scope, k))
+		}
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	infos := ActionInfos()
+	var entries []helpEntry
+	for action, info := range infos {
+		keys := keysByAction[action]
+		sort.Strings(keys)
+		entry := helpEntry{action, keys, info.Category, info.Description}
+		text := strings.ToLower(strings.Join([]string{strings.Join(keys, " "), action, info.Category, info.Description}, " "))
+		if query != "" && !strings.Contains(text, query) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	order := make(map[string]int)
+	for i, category := range actionCategories {
+		order[category] = i
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Category != b.Category {
+			return order[a.Category] < order[b.Category]
+		}
+		return a.Action < b.Action
+	})
+
+	return entries
+}
+
+// helpKeys returns the shortcut keys of the entry, or a placeholder if the action isn't bound to a key
+func (e helpEntry) helpKeys() string {
+	if len(e.Keys) == 0 {
+		return "(unbound)"
+	}
+
+	return strings.Join(e.Keys, " ")
+}
+
+// helpLines returns the help entries as lines grouped under their category, with the category
+// lines formatted by the header function and the entry lines by the entry function
+func helpLines(entries []helpEntry, header func(string) string, entry func(helpEntry) string) []string {
+	var lines []string
+	category := ""
+	for _, e := range entries {
+		if e.Category != category {
+			if category != "" {
+				lines = append(lines, "")
+			}
+			category = e.Category
+			lines = append(lines, header(category))
+		}
+		lines = append(lines, entry(e))
+	}
+
+	return lines
+}
+
 func (ct *Cointop) updateHelp() {
 	ct.debuglog("updateHelp()")
-	keys := make([]string, 0, len(ct.State.shortcutKeys))
-	for k := range ct.State.shortcutKeys {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	header := ct.colorscheme.MenuHeader(fmt.Sprintf(" Help %s\n\n", pad.Left("[q] close ", ct.maxTableWidth-10, " ")))
-	cnt := 0
-	h := ct.Views.Help.Height()
-	percol := h - 6
-	cols := make([][]string, percol)
-	for i := range cols {
-		cols[i] = make([]string, 20)
-	}
-	for _, k := range keys {
-		v := ct.State.shortcutKeys[k]
-		if cnt%percol == 0 {
-			cnt = 0
+	header := ct.colorscheme.MenuHeader(fmt.Sprintf(" Help %s\n\n", pad.Left("[q] close ", ct.ClampedWidth()-10, " ")))
+
+	entries := ct.helpEntries(ct.State.helpFilter)
+	lines := helpLines(entries, func(category string) string {
+		return fmt.Sprintf(" %s", ct.colorscheme.MenuLabelActive(category))
+	}, func(e helpEntry) string {
+		return fmt.Sprintf("   %-18s %s %s", e.helpKeys(), ct.colorscheme.MenuLabel(fmt.Sprintf("%-34s", e.Action)), e.Description)
+	})
+	if len(lines) == 0 {
+		lines = []string{" No matching actions"}
+	}
+	if len(ct.State.keyConflicts) > 0 {
+		conflicts := []string{fmt.Sprintf(" %s", ct.colorscheme.MenuLabelActive("Key conflicts"))}
+		for _, conflict := range ct.State.keyConflicts {
+			conflicts = append(conflicts, fmt.Sprintf("   %s", conflict))
 		}
-		item := fmt.Sprintf("%10s %-40s", k, ct.colorscheme.MenuLabel(v))
-		cols[cnt] = append(cols[cnt], item)
-		cnt = cnt + 1
+		lines = append(append(conflicts, ""), lines...)
 	}
 
-	var body string
-	for i := 0; i < percol; i++ {
-		var row string
-		for j := 0; j < len(cols[i]); j++ {
-			item := cols[i][j]
-			row = fmt.Sprintf("%s%s", row, item)
-		}
-		body = fmt.Sprintf("%s%s\n", body, row)
+	// NOTE: the header, filter line and version line take 6 rows
+	rows := ct.helpRows()
+	if ct.State.helpOffset > len(lines)-rows {
+		ct.State.helpOffset = len(lines) - rows
 	}
-	body = fmt.Sprintf("%s\n", body)
+	if ct.State.helpOffset < 0 {
+		ct.State.helpOffset = 0
+	}
+	end := ct.State.helpOffset + rows
+	if end > len(lines) {
+		end = len(lines)
+	}
+	body := strings.Join(lines[ct.State.helpOffset:end], "\n")
+	body = fmt.Sprintf("%s%s\n", body, strings.Repeat("\n", rows-(end-ct.State.helpOffset)))
 
-	infoline := " List of keyboard shortcuts\n\n"
-	versionline := pad.Left(fmt.Sprintf("v%s", ct.Version()), ct.maxTableWidth-5, " ")
+	infoline := " Type to filter the actions, [↑ ↓] scroll\n\n"
+	if ct.State.helpFilter != "" {
+		infoline = fmt.Sprintf(" Filter: %s\n\n", ct.colorscheme.MenuLabelActive(ct.State.helpFilter))
+	}
+	versionline := pad.Left(fmt.Sprintf("v%s", ct.Version()), ct.ClampedWidth()-5, " ")
 	content := header + infoline + body + versionline
 
 	ct.Update(func() error {
@@ -70,9 +157,25 @@ func (ct *Cointop) updateHelp() {
 	})
 }
 
+// helpRows returns the number of help lines which fit in the help view
+func (ct *Cointop) helpRows() int {
+	if ct.Views.Help.Backing() == nil {
+		return 1
+	}
+
+	rows := ct.Views.Help.Height() - 6
+	if rows < 1 {
+		return 1
+	}
+
+	return rows
+}
+
 func (ct *Cointop) showHelp() error {
 	ct.debuglog("showHelp()")
 	ct.State.helpVisible = true
+	ct.State.helpFilter = ""
+	ct.State.helpOffset = 0
 	ct.updateHelp()
 	ct.SetActiveView(ct.Views.Help.Name())
 	return nil
@@ -104,3 +207,58 @@ func (ct *Cointop) toggleHelp() error {
 	}
 	return ct.hideHelp()
 }
+
+// helpEscape clears the help filter, or hides the help if there's no filter
+func (ct *Cointop) helpEscape() error {
+	ct.debuglog("helpEscape()")
+	if ct.State.helpFilter == "" {
+		return ct.hideHelp()
+	}
+
+	ct.State.helpFilter = ""
+	ct.State.helpOffset = 0
+	ct.updateHelp()
+	return nil
+}
+
+// helpScrollFn returns a function that scrolls the help down by the number of lines, or up if negative
+func (ct *Cointop) helpScrollFn(delta int) func() error {
+	return func() error {
+		ct.debuglog("helpScrollFn()")
+		ct.State.helpOffset += delta
+		ct.updateHelp()
+		return nil
+	}
+}
+
+// helpPageScrollFn returns a function that scrolls the help down by a page, or up if negative
+func (ct *Cointop) helpPageScrollFn(direction int) func() error {
+	return func() error {
+		return ct.helpScrollFn(direction * ct.helpRows())()
+	}
+}
+
+// helpEditor updates the help filter as it's typed. The q key closes the help when there's no filter
+func (ct *Cointop) helpEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	filter := []rune(ct.State.helpFilter)
+	switch {
+	case key == gocui.KeyBackspace || key == gocui.KeyBackspace2:
+		if len(filter) == 0 {
+			return
+		}
+		filter = filter[:len(filter)-1]
+	case key == gocui.KeySpace:
+		filter = append(filter, ' ')
+	case ch == 'q' && len(filter) == 0:
+		ct.hideHelp()
+		return
+	case ch != 0 && mod == gocui.ModNone:
+		filter = append(filter, ch)
+	default:
+		return
+	}
+
+	ct.State.helpFilter = string(filter)
+	ct.State.helpOffset = 0
+	ct.updateHelp()
+}
@@ -0,0 +1,277 @@
+package cointop
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/cdyfng/coind/cointop/common/gizak/termui"
+	"github.com/cdyfng/coind/cointop/common/indicators"
+)
+
+// IndicatorView is structure for indicator panel view
+type IndicatorView struct {
+	*View
+}
+
+// NewIndicatorView returns a new indicator panel view
+func NewIndicatorView() *IndicatorView {
+	return &IndicatorView{NewView("indicator")}
+}
+
+// indicatorHeight is the height of the indicator panel
+const indicatorHeight = 6
+
+// chartOverlays are the indicators which can be drawn over the price chart
+var chartOverlays = []string{"sma", "ema", "bollinger"}
+
+// indicatorPanels are the indicators which can be shown in the indicator panel
+var indicatorPanels = []string{"rsi", "macd"}
+
+// isChartOverlay returns true if the name is a chart overlay
+func (ct *Cointop) isChartOverlay(name string) bool {
+	for _, overlay := range chartOverlays {
+		if overlay == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isIndicatorPanel returns true if the name is an indicator panel
+func (ct *Cointop) isIndicatorPanel(name string) bool {
+	for _, panel := range indicatorPanels {
+		if panel == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indicatorSettings are the periods of the chart indicators
+type indicatorSettings struct {
+	SMAPeriod           int
+	EMAPeriod           int
+	BollingerPeriod     int
+	BollingerDeviations float64
+	RSIPeriod           int
+	MACDFast            int
+	MACDSlow            int
+	MACDSignal          int
+}
+
+// defaultIndicatorSettings returns the commonly used indicator periods
+func defaultIndicatorSettings() indicatorSettings {
+	return indicatorSettings{
+		SMAPeriod:           20,
+		EMAPeriod:           50,
+		BollingerPeriod:     20,
+		BollingerDeviations: 2,
+		RSIPeriod:           14,
+		MACDFast:            12,
+		MACDSlow:            26,
+		MACDSignal:          9,
+	}
+}
+
+// hasChartOverlays returns true if any overlay is enabled
+func (ct *Cointop) hasChartOverlays() bool {
+	for _, name := range chartOverlays {
+		if ct.State.chartOverlays[name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyChartOverlays draws the enabled overlays under the price line of the chart
+// and returns the legend labels of the overlays and their colors
+func (ct *Cointop) applyChartOverlays(chart *termui.LineChart, values []float64) ([]string, []termui.Attribute) {
+	s := ct.State.indicatorSettings
+	var labels []string
+	var tags []termui.Attribute
+	for i, name := range chartOverlays {
+		if !ct.State.chartOverlays[name] {
+			continue
+		}
+
+		// NOTE: the overlay color is the index into the chart colors plus one
+		ct.State.chartColors = append(ct.State.chartColors, ct.colorscheme.ChartSeriesSprintf(i+1))
+		tag := termui.Attribute(len(ct.State.chartColors))
+		switch name {
+		case "sma":
+			chart.MultiData = append(chart.MultiData, indicators.SMA(values, s.SMAPeriod))
+			chart.LineColors = append(chart.LineColors, tag)
+			labels = append(labels, fmt.Sprintf("SMA(%d)", s.SMAPeriod))
+		case "ema":
+			chart.MultiData = append(chart.MultiData, indicators.EMA(values, s.EMAPeriod))
+			chart.LineColors = append(chart.LineColors, tag)
+			labels = append(labels, fmt.Sprintf("EMA(%d)", s.EMAPeriod))
+		case "bollinger":
+			upper, middle, lower := indicators.Bollinger(values, s.BollingerPeriod, s.BollingerDeviations)
+			chart.MultiData = append(chart.MultiData, upper, middle, lower)
+			chart.LineColors = append(chart.LineColors, tag, tag, tag)
+			labels = append(labels, fmt.Sprintf("BB(%d,%g)", s.BollingerPeriod, s.BollingerDeviations))
+		}
+		tags = append(tags, tag)
+	}
+
+	if len(labels) > 0 {
+		// NOTE: the price line is drawn last so it's on top of the overlays
+		chart.MultiData = append(chart.MultiData, values)
+		chart.LineColors = append(chart.LineColors, termui.ColorDefault)
+	}
+
+	return labels, tags
+}
+
+// legendCells writes the labels right aligned in the first row of the points, each tagged with its color
+func legendCells(points [][]termui.Cell, labels []string, tags []termui.Attribute) {
+	if len(points) == 0 || len(labels) == 0 {
+		return
+	}
+
+	row := points[0]
+	var runes []rune
+	var runeTags []termui.Attribute
+	for i, label := range labels {
+		for _, r := range fmt.Sprintf(" %s", label) {
+			runes = append(runes, r)
+			runeTags = append(runeTags, tags[i])
+		}
+	}
+
+	start := len(row) - len(runes) - 1
+	if start < 0 {
+		return
+	}
+	for i, r := range runes {
+		row[start+i] = termui.Cell{Ch: r, Fg: runeTags[i], Bg: row[start+i].Bg}
+	}
+}
+
+// indicatorCells returns the cells of the indicator panel aligned to the plotted columns of the chart
+func (ct *Cointop) indicatorCells(chart *termui.LineChart, values []float64) ([][]termui.Cell, []ISprintf) {
+	ct.debuglog("indicatorCells()")
+	panel := ct.State.indicatorPanel
+	offset, n := chart.PlotColumns()
+	if panel == "" || len(values) == 0 || n == 0 {
+		return nil, nil
+	}
+
+	// NOTE: in candle mode each plotted column is a candle, so the panel data is
+	// resampled to the two values per column of the braille line
+	if chart.Mode == "candle" {
+		values = resampleSeries(values, 2*n)
+	}
+
+	s := ct.State.indicatorSettings
+	var series [][]float64
+	var tags []termui.Attribute
+	var colors []ISprintf
+	var label string
+	format := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	switch panel {
+	case "rsi":
+		rsi := indicators.RSI(values, s.RSIPeriod)
+		if math.IsNaN(rsi[len(rsi)-1]) {
+			return nil, nil
+		}
+		// NOTE: the overbought and oversold lines are drawn in the chart color
+		series = [][]float64{constantSeries(30, len(values)), constantSeries(70, len(values)), rsi}
+		tags = []termui.Attribute{termui.ColorDefault, termui.ColorDefault, termui.Attribute(1)}
+		colors = []ISprintf{ct.colorscheme.ChartSeriesSprintf(1)}
+		label = fmt.Sprintf("RSI(%d) %.2f", s.RSIPeriod, rsi[len(rsi)-1])
+	case "macd":
+		macd, signal, _ := indicators.MACD(values, s.MACDFast, s.MACDSlow, s.MACDSignal)
+		if math.IsNaN(signal[len(signal)-1]) {
+			return nil, nil
+		}
+		// NOTE: the macd line is drawn last so it's on top of the signal line
+		series = [][]float64{signal, macd}
+		tags = []termui.Attribute{termui.Attribute(1), termui.Attribute(2)}
+		colors = []ISprintf{ct.colorscheme.ChartSeriesSprintf(1), ct.colorscheme.ChartSeriesSprintf(0)}
+		format = chartLabelFormat(macd)
+		label = fmt.Sprintf("MACD(%d,%d,%d) %s", s.MACDFast, s.MACDSlow, s.MACDSignal, format(macd[len(macd)-1]))
+	}
+
+	pchart := termui.NewLineChart()
+	// NOTE: the extra row is the x-axis label row which isn't shown
+	pchart.Height = indicatorHeight + 1
+	pchart.Border = false
+	pchart.AxesColor = termui.ColorDefault
+	pchart.DataLabels = []string{""}
+	pchart.MultiData = series
+	pchart.LineColors = tags
+	// NOTE: the y-axis labels are padded to the width of the chart labels so the columns line up
+	pchart.YLabelFormat = func(v float64) string {
+		return fmt.Sprintf("%*s", offset-1, format(v))
+	}
+
+	points := ct.chartCells(pchart)[:indicatorHeight]
+	legendCells(points, []string{label}, []termui.Attribute{tags[len(tags)-1]})
+
+	return points, colors
+}
+
+// constantSeries returns a series of n values of v
+func constantSeries(v float64, n int) []float64 {
+	series := make([]float64, n)
+	for i := range series {
+		series[i] = v
+	}
+
+	return series
+}
+
+// updateIndicator renders the indicator panel points. It must be called from an update
+func (ct *Cointop) updateIndicator() {
+	ct.debuglog("updateIndicator()")
+	if ct.Views.Indicator.Backing() == nil {
+		return
+	}
+
+	body := ct.colorscheme.Chart("\n\nnot enough data for indicator")
+	if len(ct.State.indicatorPoints) > 0 {
+		body = ct.chartBody(ct.State.indicatorPoints, ct.State.indicatorColors, "")
+	}
+
+	ct.Views.Indicator.Backing().Clear()
+	fmt.Fprint(ct.Views.Indicator.Backing(), body)
+}
+
+// toggleChartOverlayFn returns a function that toggles the overlay on the price chart
+func (ct *Cointop) toggleChartOverlayFn(name string) func() error {
+	return func() error {
+		ct.debuglog("toggleChartOverlayFn()")
+		ct.State.chartOverlays[name] = !ct.State.chartOverlays[name]
+
+		if err := ct.Save(); err != nil {
+			return err
+		}
+
+		go ct.UpdateChart()
+		return nil
+	}
+}
+
+// toggleIndicatorPanelFn returns a function that shows the indicator in the indicator panel, or hides the panel
+func (ct *Cointop) toggleIndicatorPanelFn(name string) func() error {
+	return func() error {
+		ct.debuglog("toggleIndicatorPanelFn()")
+		if ct.State.indicatorPanel == name {
+			ct.State.indicatorPanel = ""
+		} else {
+			ct.State.indicatorPanel = name
+		}
+
+		if err := ct.Save(); err != nil {
+			return err
+		}
+
+		go ct.UpdateChart()
+		return nil
+	}
+}
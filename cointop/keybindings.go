@@ -1,6 +1,7 @@
 package cointop
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/miguelmota/gocui"
@@ -206,150 +207,214 @@ func (ct *Cointop) parseKeys(s string) (interface{}, gocui.Modifier) {
 	return key, mod
 }
 
-func (ct *Cointop) keybindings(g *gocui.Gui) error {
-	for k, v := range ct.State.shortcutKeys {
-		if k == "" {
-			continue
-		}
-		v = strings.TrimSpace(strings.ToLower(v))
-		var fn func(g *gocui.Gui, v *gocui.View) error
-		key, mod := ct.parseKeys(k)
-		view := "table"
-		switch v {
-		case "move_up":
-			fn = ct.keyfn(ct.cursorUp)
-		case "move_down":
-			fn = ct.keyfn(ct.cursorDown)
-		case "previous_page":
-			fn = ct.handleHkey(key)
-		case "next_page":
-			fn = ct.keyfn(ct.nextPage)
-		case "page_down":
-			fn = ct.keyfn(ct.pageDown)
-		case "page_up":
-			fn = ct.keyfn(ct.pageUp)
-		case "sort_column_symbol":
-			fn = ct.sortfn("symbol", false)
-		case "move_to_page_first_row":
-			fn = ct.keyfn(ct.navigateFirstLine)
-		case "move_to_page_last_row":
-			fn = ct.keyfn(ct.navigateLastLine)
-		case "open_link":
-			fn = ct.keyfn(ct.OpenLink)
-		case "refresh":
-			fn = ct.keyfn(ct.refresh)
-		case "sort_column_asc":
-			fn = ct.keyfn(ct.sortAsc)
-		case "sort_column_desc":
-			fn = ct.keyfn(ct.sortDesc)
-		case "sort_left_column":
-			fn = ct.keyfn(ct.sortPrevCol)
-		case "sort_right_column":
-			fn = ct.keyfn(ct.sortNextCol)
-		case "help":
-			fallthrough
-		case "toggle_show_help":
-			fn = ct.keyfn(ct.toggleHelp)
-			view = ""
-		case "show_help":
-			fn = ct.keyfn(ct.showHelp)
-			view = ""
-		case "hide_help":
-			fn = ct.keyfn(ct.hideHelp)
-			view = "help"
-		case "first_page":
-			fn = ct.keyfn(ct.firstPage)
-		case "sort_column_1h_change":
-			fn = ct.sortfn("1hchange", true)
-		case "sort_column_24h_change":
-			fn = ct.sortfn("24hchange", true)
-		case "sort_column_7d_change":
-			fn = ct.sortfn("7dchange", true)
-		case "sort_column_30d_change":
-			fn = ct.sortfn("30dchange", true)	
-		case "sort_column_1y_change":
-			fn = ct.sortfn("1ychange", true)						
-		case "sort_column_available_supply":
-			fn = ct.sortfn("availablesupply", true)
-		case "toggle_row_chart":
-			fn = ct.keyfn(ct.ToggleCoinChart)
-		case "move_to_page_visible_first_row":
-			fn = ct.keyfn(ct.navigatePageFirstLine)
-		case "move_to_page_visible_last_row":
-			fn = ct.keyfn(ct.navigatePageLastLine)
-		case "sort_column_market_cap":
-			fn = ct.sortfn("marketcap", true)
-		case "move_to_page_visible_middle_row":
-			fn = ct.keyfn(ct.navigatePageMiddleLine)
-		case "sort_column_name":
-			fn = ct.sortfn("name", false)
-		case "sort_column_price":
-			fn = ct.sortfn("price", true)
-		case "sort_column_rank":
-			fn = ct.sortfn("rank", false)
-		case "sort_column_total_supply":
-			fn = ct.sortfn("totalsupply", true)
-		case "sort_column_last_updated":
-			fn = ct.sortfn("lastupdated", true)
-		case "sort_column_24h_volume":
-			fn = ct.sortfn("24hvolume", true)
-		case "sort_column_balance":
-			fn = ct.sortfn("balance", true)
-		case "sort_column_holdings":
-			fn = ct.sortfn("holdings", true)
-		case "last_page":
-			fn = ct.keyfn(ct.lastPage)
-		case "open_search":
-			fn = ct.keyfn(ct.openSearch)
-			view = ""
-		case "toggle_favorite":
-			fn = ct.keyfn(ct.toggleFavorite)
-		case "toggle_show_favorites":
-			fn = ct.keyfn(ct.toggleShowFavorites)
-		case "save":
-			fn = ct.keyfn(ct.Save)
-		case "quit":
-			fn = ct.keyfn(ct.Quit)
-			view = ""
-		case "quit_view":
-			fn = ct.keyfn(ct.QuitView)
-		case "next_chart_range":
-			fn = ct.keyfn(ct.NextChartRange)
-		case "previous_chart_range":
-			fn = ct.keyfn(ct.PrevChartRange)
-		case "first_chart_range":
-			fn = ct.keyfn(ct.FirstChartRange)
-		case "last_chart_range":
-			fn = ct.keyfn(ct.LastChartRange)
-		case "toggle_show_currency_convert_menu":
-			fn = ct.keyfn(ct.toggleConvertMenu)
-		case "show_currency_convert_menu":
-			fn = ct.keyfn(ct.showConvertMenu)
-		case "hide_currency_convert_menu":
-			fn = ct.keyfn(ct.hideConvertMenu)
-			view = "convertmenu"
-		case "toggle_portfolio":
-			fn = ct.keyfn(ct.togglePortfolio)
-		case "toggle_show_portfolio":
-			fn = ct.keyfn(ct.toggleShowPortfolio)
-		case "show_portfolio_edit_menu":
-			fn = ct.keyfn(ct.togglePortfolioUpdateMenu)
-		case "toggle_table_fullscreen":
-			fn = ct.keyfn(ct.ToggleTableFullscreen)
-			view = ""
-		case "enlarge_chart":
-			fn = ct.keyfn(ct.EnlargeChart)
-		case "shorten_chart":
-			fn = ct.keyfn(ct.ShortenChart)
-		case "move_down_or_next_page":
-			fn = ct.keyfn(ct.CursorDownOrNextPage)
-		case "move_up_or_previous_page":
-			fn = ct.keyfn(ct.CursorUpOrPreviousPage)
-		default:
-			fn = ct.keyfn(ct.noop)
-		}
+// actionHandler returns the handler of the action and the view it's bound to. The key is
+// the shortcut key the action is bound to, which some actions depend on
+func (ct *Cointop) actionHandler(action string, key interface{}) (func(g *gocui.Gui, v *gocui.View) error, string) {
+	action = strings.TrimSpace(strings.ToLower(action))
+	var fn func(g *gocui.Gui, v *gocui.View) error
+	view := "table"
+	switch action {
+	case "move_up":
+		fn = ct.keyfn(ct.cursorUp)
+	case "move_down":
+		fn = ct.keyfn(ct.cursorDown)
+	case "previous_page":
+		fn = ct.handleHkey(key)
+	case "next_page":
+		fn = ct.keyfn(ct.nextPage)
+	case "page_down":
+		fn = ct.keyfn(ct.pageDown)
+	case "page_up":
+		fn = ct.keyfn(ct.pageUp)
+	case "sort_column_symbol":
+		fn = ct.sortfn("symbol", false)
+	case "move_to_page_first_row":
+		fn = ct.keyfn(ct.navigateFirstLine)
+	case "move_to_page_last_row":
+		fn = ct.keyfn(ct.navigateLastLine)
+	case "open_link":
+		fn = ct.keyfn(ct.OpenLink)
+	case "refresh":
+		fn = ct.keyfn(ct.refresh)
+	case "sort_column_asc":
+		fn = ct.keyfn(ct.sortAsc)
+	case "sort_column_desc":
+		fn = ct.keyfn(ct.sortDesc)
+	case "sort_left_column":
+		fn = ct.keyfn(ct.sortPrevCol)
+	case "sort_right_column":
+		fn = ct.keyfn(ct.sortNextCol)
+	case "help":
+		fallthrough
+	case "toggle_show_help":
+		fn = ct.keyfn(ct.toggleHelp)
+		view = ""
+	case "show_help":
+		fn = ct.keyfn(ct.showHelp)
+		view = ""
+	case "close_help":
+		fallthrough
+	case "hide_help":
+		fn = ct.keyfn(ct.hideHelp)
+		view = "help"
+	case "first_page":
+		fn = ct.keyfn(ct.firstPage)
+	case "sort_column_1h_change":
+		fn = ct.sortfn("1hchange", true)
+	case "sort_column_24h_change":
+		fn = ct.sortfn("24hchange", true)
+	case "sort_column_7d_change":
+		fn = ct.sortfn("7dchange", true)
+	case "sort_column_30d_change":
+		fn = ct.sortfn("30dchange", true)
+	case "sort_column_1y_change":
+		fn = ct.sortfn("1ychange", true)
+	case "sort_column_available_supply":
+		fn = ct.sortfn("availablesupply", true)
+	case "toggle_row_chart":
+		fn = ct.keyfn(ct.ToggleCoinChart)
+	case "move_to_page_visible_first_row":
+		fn = ct.keyfn(ct.navigatePageFirstLine)
+	case "move_to_page_visible_last_row":
+		fn = ct.keyfn(ct.navigatePageLastLine)
+	case "sort_column_market_cap":
+		fn = ct.sortfn("marketcap", true)
+	case "move_to_page_visible_middle_row":
+		fn = ct.keyfn(ct.navigatePageMiddleLine)
+	case "sort_column_name":
+		fn = ct.handleNkey(key)
+	case "next_search_match":
+		fn = ct.keyfn(ct.nextSearchMatch)
+	case "previous_search_match":
+		fn = ct.keyfn(ct.previousSearchMatch)
+	case "sort_column_price":
+		fn = ct.sortfn("price", true)
+	case "sort_column_rank":
+		fn = ct.sortfn("rank", false)
+	case "sort_column_total_supply":
+		fn = ct.sortfn("totalsupply", true)
+	case "sort_column_last_updated":
+		fn = ct.sortfn("lastupdated", true)
+	case "sort_column_24h_volume":
+		fn = ct.sortfn("24hvolume", true)
+	case "sort_column_balance":
+		fn = ct.sortfn("balance", true)
+	case "sort_column_holdings":
+		fn = ct.sortfn("holdings", true)
+	case "last_page":
+		fn = ct.keyfn(ct.lastPage)
+	case "open_search":
+		fn = ct.keyfn(ct.openSearch)
+		view = ""
+	case "open_filter":
+		fn = ct.keyfn(ct.openFilter)
+	case "clear_filter":
+		fn = ct.keyfn(ct.clearFilter)
+	case "open_command_palette":
+		fn = ct.keyfn(ct.openCommandPalette)
+	case "toggle_favorite":
+		fn = ct.keyfn(ct.toggleFavorite)
+	case "toggle_show_favorites":
+		fn = ct.keyfn(ct.toggleShowFavorites)
+	case "show_watchlists_menu":
+		fn = ct.keyfn(ct.showWatchlistsMenu)
+	case "show_colorscheme_menu":
+		fn = ct.keyfn(ct.showColorschemeMenu)
+	case "toggle_colorscheme_menu":
+		fn = ct.keyfn(ct.toggleColorschemeMenu)
+	case "show_top_movers":
+		fn = ct.keyfn(ct.showTopMovers)
+	case "toggle_top_movers":
+		fn = ct.keyfn(ct.toggleTopMovers)
+	case "show_calculator":
+		fn = ct.keyfn(ct.showCalculator)
+	case "toggle_calculator":
+		fn = ct.keyfn(ct.toggleCalculator)
+	case "toggle_watchlists_menu":
+		fn = ct.keyfn(ct.toggleWatchlistsMenu)
+	case "next_watchlist":
+		fn = ct.keyfn(ct.nextWatchlist)
+	case "toggle_compare_coin":
+		fn = ct.keyfn(ct.ToggleCompareCoin)
+	case "toggle_compare_chart":
+		fn = ct.keyfn(ct.ToggleCompareChart)
+	case "clear_compare_coins":
+		fn = ct.keyfn(ct.ClearCompareCoins)
+	case "save":
+		fn = ct.keyfn(ct.Save)
+	case "quit":
+		fn = ct.keyfn(ct.Quit)
+		view = ""
+	case "quit_view":
+		fn = ct.keyfn(ct.QuitView)
+	case "enlarge_volume_chart":
+		fn = ct.keyfn(ct.EnlargeVolume)
+	case "shorten_volume_chart":
+		fn = ct.keyfn(ct.ShortenVolume)
+	case "toggle_chart_inspect":
+		fn = ct.keyfn(ct.ToggleChartInspect)
+	case "toggle_chart_sma":
+		fn = ct.keyfn(ct.toggleChartOverlayFn("sma"))
+	case "toggle_chart_ema":
+		fn = ct.keyfn(ct.toggleChartOverlayFn("ema"))
+	case "toggle_chart_bollinger":
+		fn = ct.keyfn(ct.toggleChartOverlayFn("bollinger"))
+	case "toggle_chart_rsi":
+		fn = ct.keyfn(ct.toggleIndicatorPanelFn("rsi"))
+	case "toggle_chart_macd":
+		fn = ct.keyfn(ct.toggleIndicatorPanelFn("macd"))
+	case "next_chart_scale":
+		fn = ct.keyfn(ct.NextChartScale)
+	case "toggle_chart_mode":
+		fn = ct.keyfn(ct.ToggleChartMode)
+	case "next_chart_range":
+		fn = ct.keyfn(ct.NextChartRange)
+	case "previous_chart_range":
+		fn = ct.keyfn(ct.PrevChartRange)
+	case "first_chart_range":
+		fn = ct.keyfn(ct.FirstChartRange)
+	case "last_chart_range":
+		fn = ct.keyfn(ct.LastChartRange)
+	case "toggle_show_currency_convert_menu":
+		fn = ct.keyfn(ct.toggleConvertMenu)
+	case "show_currency_convert_menu":
+		fn = ct.keyfn(ct.showConvertMenu)
+	case "hide_currency_convert_menu":
+		fn = ct.keyfn(ct.hideConvertMenu)
+		view = "convertmenu"
+	case "show_table_columns_menu":
+		fn = ct.keyfn(ct.showTableColumnsMenu)
+	case "toggle_show_table_columns_menu":
+		fn = ct.keyfn(ct.toggleTableColumnsMenu)
+	case "toggle_portfolio":
+		fn = ct.keyfn(ct.togglePortfolio)
+	case "toggle_show_portfolio":
+		fn = ct.keyfn(ct.toggleShowPortfolio)
+	case "show_portfolio_edit_menu":
+		fn = ct.keyfn(ct.togglePortfolioUpdateMenu)
+	case "toggle_table_fullscreen":
+		fn = ct.keyfn(ct.ToggleTableFullscreen)
+		view = ""
+	case "enlarge_chart":
+		fn = ct.keyfn(ct.EnlargeChart)
+	case "shorten_chart":
+		fn = ct.keyfn(ct.ShortenChart)
+	case "move_down_or_next_page":
+		fn = ct.keyfn(ct.CursorDownOrNextPage)
+	case "move_up_or_previous_page":
+		fn = ct.keyfn(ct.CursorUpOrPreviousPage)
+	default:
+		fn = ct.keyfn(ct.noop)
+	}
+
+	return fn, view
+}
 
-		ct.setKeybindingMod(key, mod, fn, view)
+func (ct *Cointop) keybindings(g *gocui.Gui) error {
+	bindings := ct.shortcutBindings()
+	roots, conflicts := ct.keySequenceTries(bindings)
+	if err := ct.setKeySequenceBindings(roots); err != nil {
+		return err
 	}
 
 	// keys to force quit
@@ -359,10 +424,27 @@ func (ct *Cointop) keybindings(g *gocui.Gui) error {
 	// searchfield keys
 	ct.setKeybindingMod(gocui.KeyEnter, gocui.ModNone, ct.keyfn(ct.doSearch), ct.Views.SearchField.Name())
 	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.cancelSearch), ct.Views.SearchField.Name())
+	ct.setKeybindingMod(gocui.KeyArrowDown, gocui.ModNone, ct.keyfn(ct.searchResultsCursorFn(1)), ct.Views.SearchField.Name())
+	ct.setKeybindingMod(gocui.KeyArrowUp, gocui.ModNone, ct.keyfn(ct.searchResultsCursorFn(-1)), ct.Views.SearchField.Name())
+	ct.setKeybindingMod(gocui.KeyTab, gocui.ModNone, ct.keyfn(ct.searchResultsCursorFn(1)), ct.Views.SearchField.Name())
+
+	// filterfield keys
+	ct.setKeybindingMod(gocui.KeyEnter, gocui.ModNone, ct.keyfn(ct.doFilter), ct.Views.FilterField.Name())
+	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.cancelFilter), ct.Views.FilterField.Name())
 
-	// keys to quit help when open
-	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.hideHelp), ct.Views.Help.Name())
-	ct.setKeybindingMod('q', gocui.ModNone, ct.keyfn(ct.hideHelp), ct.Views.Help.Name())
+	// commandfield keys
+	ct.setKeybindingMod(gocui.KeyEnter, gocui.ModNone, ct.keyfn(ct.doCommand), ct.Views.CommandField.Name())
+	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.cancelCommand), ct.Views.CommandField.Name())
+	ct.setKeybindingMod(gocui.KeyTab, gocui.ModNone, ct.keyfn(ct.completeCommand), ct.Views.CommandField.Name())
+	ct.setKeybindingMod(gocui.KeyArrowUp, gocui.ModNone, ct.keyfn(ct.commandHistoryFn(-1)), ct.Views.CommandField.Name())
+	ct.setKeybindingMod(gocui.KeyArrowDown, gocui.ModNone, ct.keyfn(ct.commandHistoryFn(1)), ct.Views.CommandField.Name())
+
+	// keys to scroll and quit help when open. Other keys filter the help
+	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.helpEscape), ct.Views.Help.Name())
+	ct.setKeybindingMod(gocui.KeyArrowUp, gocui.ModNone, ct.keyfn(ct.helpScrollFn(-1)), ct.Views.Help.Name())
+	ct.setKeybindingMod(gocui.KeyArrowDown, gocui.ModNone, ct.keyfn(ct.helpScrollFn(1)), ct.Views.Help.Name())
+	ct.setKeybindingMod(gocui.KeyPgup, gocui.ModNone, ct.keyfn(ct.helpPageScrollFn(-1)), ct.Views.Help.Name())
+	ct.setKeybindingMod(gocui.KeyPgdn, gocui.ModNone, ct.keyfn(ct.helpPageScrollFn(1)), ct.Views.Help.Name())
 
 	// keys to quit portfolio update menu when open
 	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.hidePortfolioUpdateMenu), ct.Views.Input.Name())
@@ -371,6 +453,21 @@ func (ct *Cointop) keybindings(g *gocui.Gui) error {
 	// keys to update portfolio holdings
 	ct.setKeybindingMod(gocui.KeyEnter, gocui.ModNone, ct.keyfn(ct.setPortfolioHoldings), ct.Views.Input.Name())
 
+	// top movers keys
+	topMovers := ct.Views.TopMovers.Name()
+	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.hideTopMovers), topMovers)
+	ct.setKeybindingMod('q', gocui.ModNone, ct.keyfn(ct.hideTopMovers), topMovers)
+	ct.setKeybindingMod('1', gocui.ModNone, ct.keyfn(ct.topMoversPeriodFn("1h")), topMovers)
+	ct.setKeybindingMod('2', gocui.ModNone, ct.keyfn(ct.topMoversPeriodFn("24h")), topMovers)
+	ct.setKeybindingMod('7', gocui.ModNone, ct.keyfn(ct.topMoversPeriodFn("7d")), topMovers)
+	ct.setKeybindingMod('r', gocui.ModNone, ct.keyfn(ct.toggleTopMoversDaily), topMovers)
+
+	// keys to quit calculator when open
+	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.hideCalculator), ct.Views.CalculatorInput.Name())
+
+	// keys to copy the calculator result into the portfolio
+	ct.setKeybindingMod(gocui.KeyEnter, gocui.ModNone, ct.keyfn(ct.calculatorToPortfolio), ct.Views.CalculatorInput.Name())
+
 	// keys to quit convert menu when open
 	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.hideConvertMenu), ct.Views.ConvertMenu.Name())
 	ct.setKeybindingMod('q', gocui.ModNone, ct.keyfn(ct.hideConvertMenu), ct.Views.ConvertMenu.Name())
@@ -379,13 +476,90 @@ func (ct *Cointop) keybindings(g *gocui.Gui) error {
 	// TODO: use scrolling table
 	keys := ct.sortedSupportedCurrencyConversions()
 	for i, k := range keys {
-		ct.setKeybindingMod(rune(alphanumericcharacters[i]), gocui.ModNone, ct.keyfn(ct.setCurrencyConverstionFn(k)), ct.Views.ConvertMenu.Name())
+		ct.setKeybindingMod(rune(alphanumericcharacters[i]), gocui.ModNone, ct.keyfn(ct.setCurrencyMenuSelectionFn(k)), ct.Views.ConvertMenu.Name())
+	}
+
+	// keys to switch to and clear the secondary currency selection
+	ct.setKeybindingMod(gocui.KeyTab, gocui.ModNone, ct.keyfn(ct.toggleConvertMenuSecondary), ct.Views.ConvertMenu.Name())
+	ct.setKeybindingMod('-', gocui.ModNone, ct.keyfn(ct.disableSecondaryCurrency), ct.Views.ConvertMenu.Name())
+
+	// watchlists menu keys
+	watchlistsMenu := ct.Views.WatchlistsMenu.Name()
+	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.hideWatchlistsMenu), watchlistsMenu)
+	ct.setKeybindingMod('q', gocui.ModNone, ct.keyfn(ct.hideWatchlistsMenu), watchlistsMenu)
+	ct.setKeybindingMod(gocui.KeyEnter, gocui.ModNone, ct.keyfn(ct.watchlistsMenuShow), watchlistsMenu)
+	ct.setKeybindingMod(gocui.KeyArrowUp, gocui.ModNone, ct.keyfn(ct.watchlistsMenuCursorFn(-1)), watchlistsMenu)
+	ct.setKeybindingMod('k', gocui.ModNone, ct.keyfn(ct.watchlistsMenuCursorFn(-1)), watchlistsMenu)
+	ct.setKeybindingMod(gocui.KeyArrowDown, gocui.ModNone, ct.keyfn(ct.watchlistsMenuCursorFn(1)), watchlistsMenu)
+	ct.setKeybindingMod('j', gocui.ModNone, ct.keyfn(ct.watchlistsMenuCursorFn(1)), watchlistsMenu)
+	ct.setKeybindingMod(gocui.KeySpace, gocui.ModNone, ct.keyfn(ct.watchlistsMenuToggle), watchlistsMenu)
+	ct.setKeybindingMod('d', gocui.ModNone, ct.keyfn(ct.watchlistsMenuDelete), watchlistsMenu)
+
+	colorschemeMenu := ct.Views.ColorschemeMenu.Name()
+	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.colorschemeMenuCancel), colorschemeMenu)
+	ct.setKeybindingMod('q', gocui.ModNone, ct.keyfn(ct.colorschemeMenuCancel), colorschemeMenu)
+	ct.setKeybindingMod(gocui.KeyEnter, gocui.ModNone, ct.keyfn(ct.colorschemeMenuSelect), colorschemeMenu)
+	ct.setKeybindingMod(gocui.KeyArrowUp, gocui.ModNone, ct.keyfn(ct.colorschemeMenuCursorFn(-1)), colorschemeMenu)
+	ct.setKeybindingMod('k', gocui.ModNone, ct.keyfn(ct.colorschemeMenuCursorFn(-1)), colorschemeMenu)
+	ct.setKeybindingMod(gocui.KeyArrowDown, gocui.ModNone, ct.keyfn(ct.colorschemeMenuCursorFn(1)), colorschemeMenu)
+	ct.setKeybindingMod('j', gocui.ModNone, ct.keyfn(ct.colorschemeMenuCursorFn(1)), colorschemeMenu)
+
+	columnsMenu := ct.Views.TableColumnsMenu.Name()
+	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.hideTableColumnsMenu), columnsMenu)
+	ct.setKeybindingMod('q', gocui.ModNone, ct.keyfn(ct.hideTableColumnsMenu), columnsMenu)
+	ct.setKeybindingMod(gocui.KeyEnter, gocui.ModNone, ct.keyfn(ct.columnsMenuApply), columnsMenu)
+	ct.setKeybindingMod(gocui.KeyArrowUp, gocui.ModNone, ct.keyfn(ct.columnsMenuCursorFn(-1)), columnsMenu)
+	ct.setKeybindingMod('k', gocui.ModNone, ct.keyfn(ct.columnsMenuCursorFn(-1)), columnsMenu)
+	ct.setKeybindingMod(gocui.KeyArrowDown, gocui.ModNone, ct.keyfn(ct.columnsMenuCursorFn(1)), columnsMenu)
+	ct.setKeybindingMod('j', gocui.ModNone, ct.keyfn(ct.columnsMenuCursorFn(1)), columnsMenu)
+	ct.setKeybindingMod(gocui.KeySpace, gocui.ModNone, ct.keyfn(ct.columnsMenuToggle), columnsMenu)
+	ct.setKeybindingMod(gocui.KeyArrowUp, gocui.ModAlt, ct.keyfn(ct.columnsMenuMoveFn(-1)), columnsMenu)
+	ct.setKeybindingMod('K', gocui.ModNone, ct.keyfn(ct.columnsMenuMoveFn(-1)), columnsMenu)
+	ct.setKeybindingMod(gocui.KeyArrowDown, gocui.ModAlt, ct.keyfn(ct.columnsMenuMoveFn(1)), columnsMenu)
+	ct.setKeybindingMod('J', gocui.ModNone, ct.keyfn(ct.columnsMenuMoveFn(1)), columnsMenu)
+	ct.setKeybindingMod('+', gocui.ModNone, ct.keyfn(ct.columnsMenuWidthFn(1)), columnsMenu)
+	ct.setKeybindingMod('=', gocui.ModNone, ct.keyfn(ct.columnsMenuWidthFn(1)), columnsMenu)
+	ct.setKeybindingMod('-', gocui.ModNone, ct.keyfn(ct.columnsMenuWidthFn(-1)), columnsMenu)
+	ct.setKeybindingMod(gocui.KeyTab, gocui.ModNone, ct.keyfn(ct.columnsMenuNextView), columnsMenu)
+	ct.setKeybindingMod('r', gocui.ModNone, ct.keyfn(ct.columnsMenuReset), columnsMenu)
+
+	// mouse buttons and wheel
+	ct.setKeybindingMod(gocui.MouseLeft, gocui.ModNone, ct.keyfn(ct.mouseTableClick), ct.Views.Table.Name())
+	ct.setKeybindingMod(gocui.MouseWheelDown, gocui.ModNone, ct.keyfn(ct.mouseTableWheelFn(1)), ct.Views.Table.Name())
+	ct.setKeybindingMod(gocui.MouseWheelUp, gocui.ModNone, ct.keyfn(ct.mouseTableWheelFn(-1)), ct.Views.Table.Name())
+	ct.setKeybindingMod(gocui.MouseLeft, gocui.ModNone, ct.keyfn(ct.mouseHeaderClick), ct.Views.TableHeader.Name())
+	ct.setKeybindingMod(gocui.MouseWheelDown, gocui.ModNone, ct.keyfn(ct.NextChartRange), ct.Views.Chart.Name())
+	ct.setKeybindingMod(gocui.MouseWheelUp, gocui.ModNone, ct.keyfn(ct.PrevChartRange), ct.Views.Chart.Name())
+	ct.setKeybindingMod(gocui.MouseLeft, gocui.ModNone, ct.keyfn(ct.mouseStatusbarClick), ct.Views.Statusbar.Name())
+
+	// chart inspect cursor keys
+	chart := ct.Views.Chart.Name()
+	ct.setKeybindingMod(gocui.KeyEsc, gocui.ModNone, ct.keyfn(ct.hideChartInspect), chart)
+	ct.setKeybindingMod('q', gocui.ModNone, ct.keyfn(ct.hideChartInspect), chart)
+	ct.setKeybindingMod('i', gocui.ModNone, ct.keyfn(ct.hideChartInspect), chart)
+	ct.setKeybindingMod(gocui.KeyArrowLeft, gocui.ModNone, ct.keyfn(ct.chartCursorFn(-1)), chart)
+	ct.setKeybindingMod('h', gocui.ModNone, ct.keyfn(ct.chartCursorFn(-1)), chart)
+	ct.setKeybindingMod(gocui.KeyArrowRight, gocui.ModNone, ct.keyfn(ct.chartCursorFn(1)), chart)
+	ct.setKeybindingMod('l', gocui.ModNone, ct.keyfn(ct.chartCursorFn(1)), chart)
+	ct.setKeybindingMod('H', gocui.ModNone, ct.keyfn(ct.chartCursorFn(-10)), chart)
+	ct.setKeybindingMod('L', gocui.ModNone, ct.keyfn(ct.chartCursorFn(10)), chart)
+
+	conflicts = append(conflicts, fixedKeyConflicts(bindings, ct.State.fixedKeys)...)
+	ct.State.keyConflicts = append(append([]string{}, ct.State.shortcutWarnings...), conflicts...)
+	for _, conflict := range ct.State.keyConflicts {
+		ct.debuglog(fmt.Sprintf("key conflict: %s", conflict))
 	}
 
 	return nil
 }
 
 func (ct *Cointop) setKeybindingMod(key interface{}, mod gocui.Modifier, callback func(g *gocui.Gui, v *gocui.View) error, view string) error {
+	// NOTE: keep track of the keys of the views to report the shortcuts which conflict with them.
+	// The global keys force quit, whatever the shortcuts are
+	if view != "" {
+		ct.State.fixedKeys = append(ct.State.fixedKeys, viewKeyPress{view, keyPress{key, mod}})
+	}
+
 	var err error
 	switch t := key.(type) {
 	case gocui.Key:
@@ -413,6 +587,17 @@ func (ct *Cointop) handleHkey(key interface{}) func(g *gocui.Gui, v *gocui.View)
 	}
 }
 
+func (ct *Cointop) handleNkey(key interface{}) func(g *gocui.Gui, v *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if k, ok := key.(rune); ok && k == 'n' && ct.hasSearchMatches() {
+			ct.nextSearchMatch()
+		} else {
+			ct.sortToggle("name", false)
+		}
+		return nil
+	}
+}
+
 func (ct *Cointop) noop() error {
 	return nil
 }
@@ -0,0 +1,297 @@
+package cointop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miguelmota/gocui"
+)
+
+// keySequenceTimeout is how long to wait for the next key of a sequence. When a sequence is also
+// the start of a longer sequence, its action runs after the timeout
+var keySequenceTimeout = 1 * time.Second
+
+// shortcutScopes are the sections of the scoped shortcuts config and the views they're bound to
+var shortcutScopes = map[string]string{
+	"global":             "",
+	"table":              "table",
+	"chart":              "chart",
+	"help":               "help",
+	"convert_menu":       "convertmenu",
+	"table_columns_menu": "tablecolumnsmenu",
+	"watchlists_menu":    "watchlistsmenu",
+	"colorscheme_menu":   "colorschememenu",
+	"top_movers":         "topmovers",
+}
+
+// keyPress is a key with its modifier
+type keyPress struct {
+	Key interface{}
+	Mod gocui.Modifier
+}
+
+// viewKeyPress is a key bound to a view
+type viewKeyPress struct {
+	View  string
+	Press keyPress
+}
+
+// shortcutBinding is a key sequence of the shortcuts config and the action it runs in the view
+type shortcutBinding struct {
+	View     string
+	Sequence string
+	Keys     []keyPress
+	Action   string
+}
+
+// keySequenceNode is a node of the key sequence trie. Nodes with an action end a sequence
+type keySequenceNode struct {
+	children map[keyPress]*keySequenceNode
+	action   string
+	fn       func(g *gocui.Gui, v *gocui.View) error
+}
+
+// keySequenceDispatcher runs the key sequences of a view as they're typed
+type keySequenceDispatcher struct {
+	root    *keySequenceNode
+	pending *keySequenceNode
+	timer   *time.Timer
+	update  func(f func() error) // runs the action of a sequence on timeout in the main loop
+}
+
+func newKeySequenceNode() *keySequenceNode {
+	return &keySequenceNode{children: make(map[keyPress]*keySequenceNode)}
+}
+
+// scopeName returns the shortcuts config section of the view
+func scopeName(view string) string {
+	for scope, v := range shortcutScopes {
+		if v == view {
+			return scope
+		}
+	}
+
+	return view
+}
+
+// parseKeySequence parses a shortcut of one or more keys separated by spaces, e.g. "g g" or "space c"
+func (ct *Cointop) parseKeySequence(s string) ([]keyPress, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		fields = []string{s}
+	}
+
+	var keys []keyPress
+	for _, field := range fields {
+		key, mod := ct.parseKeys(field)
+		if key == nil {
+			return nil, fmt.Errorf("invalid key %q", field)
+		}
+		keys = append(keys, keyPress{key, mod})
+	}
+
+	return keys, nil
+}
+
+// shortcutBindings returns the bindings of the shortcuts and the scoped shortcuts, sorted by view then sequence.
+// Shortcuts which aren't scoped are bound to the view of their action
+func (ct *Cointop) shortcutBindings() []shortcutBinding {
+	var bindings []shortcutBinding
+	add := func(view, sequence, action string) {
+		keys, err := ct.parseKeySequence(sequence)
+		if err != nil {
+			ct.debuglog(err.Error())
+			return
+		}
+		bindings = append(bindings, shortcutBinding{view, sequence, keys, strings.ToLower(action)})
+	}
+
+	for k, action := range ct.State.shortcutKeys {
+		if k == "" {
+			continue
+		}
+		_, view := ct.actionHandler(action, nil)
+		add(view, k, action)
+	}
+	for scope, shortcuts := range ct.State.scopedShortcutKeys {
+		for k, action := range shortcuts {
+			add(shortcutScopes[scope], k, action)
+		}
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		a, b := bindings[i], bindings[j]
+		if a.View != b.View {
+			return a.View < b.View
+		}
+		return a.Sequence < b.Sequence
+	})
+
+	return bindings
+}
+
+// keySequenceTries returns the key sequence trie of each view and the conflicts between the bindings.
+// Bindings conflict when the same sequence runs different actions in a view, or when a key of a view
+// is also bound globally, in which case both run
+func (ct *Cointop) keySequenceTries(bindings []shortcutBinding) (map[string]*keySequenceNode, []string) {
+	var conflicts []string
+	roots := make(map[string]*keySequenceNode)
+	for _, b := range bindings {
+		root, ok := roots[b.View]
+		if !ok {
+			root = newKeySequenceNode()
+			roots[b.View] = root
+		}
+
+		node := root
+		for _, press := range b.Keys {
+			child, ok := node.children[press]
+			if !ok {
+				child = newKeySequenceNode()
+				node.children[press] = child
+			}
+			node = child
+		}
+
+		if node.action != "" && node.action != b.Action {
+			conflicts = append(conflicts, fmt.Sprintf("%q is bound to both %s and %s in %s", b.Sequence, node.action, b.Action, scopeName(b.View)))
+			continue
+		}
+		node.action = b.Action
+		node.fn, _ = ct.actionHandler(b.Action, b.Keys[len(b.Keys)-1].Key)
+	}
+
+	for _, b := range bindings {
+		if b.View == "" {
+			continue
+		}
+		for _, global := range bindings {
+			if global.View == "" && (hasKeyPress(global.Keys, b.Keys[0]) || hasKeyPress(b.Keys, global.Keys[0])) {
+				conflicts = append(conflicts, fmt.Sprintf("%q (%s) in %s conflicts with %q (%s) in global", b.Sequence, b.Action, scopeName(b.View), global.Sequence, global.Action))
+			}
+		}
+	}
+
+	return roots, conflicts
+}
+
+// fixedKeyConflicts returns the bindings which use keys the views bind regardless of the shortcuts config
+func fixedKeyConflicts(bindings []shortcutBinding, fixed []viewKeyPress) []string {
+	var conflicts []string
+	for _, b := range bindings {
+		views := make(map[string]bool)
+		for _, f := range fixed {
+			if (b.View == "" || b.View == f.View) && hasKeyPress(b.Keys, f.Press) && !views[f.View] {
+				views[f.View] = true
+				conflicts = append(conflicts, fmt.Sprintf("%q (%s) in %s conflicts with a key of the %s view", b.Sequence, b.Action, scopeName(b.View), scopeName(f.View)))
+			}
+		}
+	}
+
+	return conflicts
+}
+
+func hasKeyPress(keys []keyPress, press keyPress) bool {
+	for _, k := range keys {
+		if k == press {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keyPresses returns all the keys of the sequences under the node
+func (node *keySequenceNode) keyPresses() []keyPress {
+	seen := make(map[keyPress]bool)
+	var keys []keyPress
+	var walk func(n *keySequenceNode)
+	walk = func(n *keySequenceNode) {
+		for press, child := range n.children {
+			if !seen[press] {
+				seen[press] = true
+				keys = append(keys, press)
+			}
+			walk(child)
+		}
+	}
+	walk(node)
+
+	return keys
+}
+
+// setKeySequenceBindings binds the keys of the shortcut sequences to their view dispatchers
+func (ct *Cointop) setKeySequenceBindings(roots map[string]*keySequenceNode) error {
+	ct.debuglog("setKeySequenceBindings()")
+	ct.State.keySequences = make(map[string]*keySequenceDispatcher)
+	for view, root := range roots {
+		ct.State.keySequences[view] = &keySequenceDispatcher{root: root, update: ct.Update}
+		for _, press := range root.keyPresses() {
+			var err error
+			switch t := press.Key.(type) {
+			case gocui.Key:
+				err = ct.g.SetKeybinding(view, t, press.Mod, ct.keySequenceFn(view, press))
+			case rune:
+				err = ct.g.SetKeybinding(view, t, press.Mod, ct.keySequenceFn(view, press))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// keySequenceFn returns the handler of the key in the view. It continues the pending sequence of
+// the view, or starts a new one, and runs the action once the sequence is complete
+func (ct *Cointop) keySequenceFn(view string, press keyPress) func(g *gocui.Gui, v *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		// NOTE: the global keys run before the editor of the help, so the characters are typed into
+		// the help filter instead
+		if ch, ok := press.Key.(rune); ok && view == "" && press.Mod == gocui.ModNone && ct.State.helpVisible && v != nil && v.Name() == ct.Views.Help.Name() {
+			ct.helpEditor(v, 0, ch, press.Mod)
+			return nil
+		}
+
+		d := ct.State.keySequences[view]
+		if d.timer != nil {
+			d.timer.Stop()
+		}
+
+		var node *keySequenceNode
+		if d.pending != nil {
+			node = d.pending.children[press]
+		}
+		d.pending = nil
+		if node == nil {
+			node = d.root.children[press]
+		}
+		if node == nil {
+			return nil
+		}
+
+		if len(node.children) == 0 {
+			return node.fn(g, v)
+		}
+
+		// NOTE: wait for the next key, or run the action of the sequence typed so far on timeout
+		d.pending = node
+		d.timer = time.AfterFunc(keySequenceTimeout, func() {
+			d.update(func() error {
+				if d.pending != node {
+					return nil
+				}
+				d.pending = nil
+				if node.fn == nil {
+					return nil
+				}
+				return node.fn(g, v)
+			})
+		})
+
+		return nil
+	}
+}
@@ -0,0 +1,234 @@
+package cointop
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/miguelmota/gocui"
+)
+
+func TestParseKeySequence(t *testing.T) {
+	ct := &Cointop{State: &State{}}
+	tests := []struct {
+		input string
+		want  []keyPress
+	}{
+		{"g", []keyPress{{'g', gocui.ModNone}}},
+		{"g g", []keyPress{{'g', gocui.ModNone}, {'g', gocui.ModNone}}},
+		{"space c", []keyPress{{gocui.KeySpace, gocui.ModNone}, {'c', gocui.ModNone}}},
+		{"ctrl+r", []keyPress{{gocui.KeyCtrlR, gocui.ModNone}}},
+		{"alt+j k", []keyPress{{'j', gocui.ModAlt}, {'k', gocui.ModNone}}},
+		{"  z  z ", []keyPress{{'z', gocui.ModNone}, {'z', gocui.ModNone}}},
+	}
+
+	for _, test := range tests {
+		got, err := ct.parseKeySequence(test.input)
+		if err != nil {
+			t.Errorf("parseKeySequence(%q) error: %v", test.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseKeySequence(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+
+	for _, input := range []string{"g notakey", "ctrl+notakey"} {
+		if _, err := ct.parseKeySequence(input); err == nil {
+			t.Errorf("parseKeySequence(%q) expected an error", input)
+		}
+	}
+}
+
+// testBinding returns the binding of the sequence, which must parse
+func testBinding(t *testing.T, ct *Cointop, view, sequence, action string) shortcutBinding {
+	keys, err := ct.parseKeySequence(sequence)
+	if err != nil {
+		t.Fatalf("parseKeySequence(%q) error: %v", sequence, err)
+	}
+
+	return shortcutBinding{view, sequence, keys, action}
+}
+
+func TestKeySequenceTries(t *testing.T) {
+	ct := &Cointop{State: &State{}}
+	tests := []struct {
+		name      string
+		bindings  [][3]string // view, sequence, action
+		actions   map[string]string
+		conflicts int
+	}{
+		{
+			name:     "single keys",
+			bindings: [][3]string{{"table", "j", "move_down"}, {"table", "k", "move_up"}},
+			actions:  map[string]string{"j": "move_down", "k": "move_up"},
+		},
+		{
+			name:     "prefix sequence",
+			bindings: [][3]string{{"table", "g", "move_to_page_first_row"}, {"table", "g g", "first_page"}},
+			actions:  map[string]string{"g": "move_to_page_first_row", "g g": "first_page"},
+		},
+		{
+			name:     "same action twice",
+			bindings: [][3]string{{"table", "g g", "first_page"}, {"table", "g g", "first_page"}},
+			actions:  map[string]string{"g g": "first_page"},
+		},
+		{
+			name:      "conflicting duplicate",
+			bindings:  [][3]string{{"table", "g g", "first_page"}, {"table", "g g", "last_page"}},
+			actions:   map[string]string{"g g": "first_page"},
+			conflicts: 1,
+		},
+		{
+			name:     "same sequence in different views",
+			bindings: [][3]string{{"chart", "x", "toggle_compare_chart"}, {"table", "x", "toggle_compare_coin"}},
+			actions:  map[string]string{"x": "toggle_compare_coin"},
+		},
+		{
+			name:      "view key also bound globally",
+			bindings:  [][3]string{{"", "q", "quit"}, {"table", "q w", "quit_view"}},
+			actions:   map[string]string{"q w": "quit_view"},
+			conflicts: 1,
+		},
+	}
+
+	for _, test := range tests {
+		var bindings []shortcutBinding
+		for _, b := range test.bindings {
+			bindings = append(bindings, testBinding(t, ct, b[0], b[1], b[2]))
+		}
+
+		roots, conflicts := ct.keySequenceTries(bindings)
+		if len(conflicts) != test.conflicts {
+			t.Errorf("%s: got conflicts %q, want %d", test.name, conflicts, test.conflicts)
+		}
+		for sequence, action := range test.actions {
+			keys, _ := ct.parseKeySequence(sequence)
+			node := roots["table"]
+			for _, press := range keys {
+				if node != nil {
+					node = node.children[press]
+				}
+			}
+			if node == nil || node.action != action || node.fn == nil {
+				t.Errorf("%s: %q doesn't run %s in table", test.name, sequence, action)
+			}
+		}
+	}
+}
+
+func TestFixedKeyConflicts(t *testing.T) {
+	ct := &Cointop{State: &State{}}
+	fixed := []viewKeyPress{
+		{"help", keyPress{gocui.KeyEsc, gocui.ModNone}},
+		{"chart", keyPress{'h', gocui.ModNone}},
+		{"chart", keyPress{'l', gocui.ModNone}},
+	}
+	bindings := []shortcutBinding{
+		testBinding(t, ct, "", "esc", "quit_view"),
+		testBinding(t, ct, "chart", "h l", "previous_chart_range"),
+		testBinding(t, ct, "table", "h", "previous_page"),
+		testBinding(t, ct, "help", "q", "hide_help"),
+	}
+
+	got := fixedKeyConflicts(bindings, fixed)
+	want := []string{
+		`"esc" (quit_view) in global conflicts with a key of the help view`,
+		`"h l" (previous_chart_range) in chart conflicts with a key of the chart view`,
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestKeySequenceTimeout(t *testing.T) {
+	timeout := keySequenceTimeout
+	keySequenceTimeout = 10 * time.Millisecond
+	defer func() { keySequenceTimeout = timeout }()
+
+	ran := make(chan string, 2)
+	record := func(action string) func(g *gocui.Gui, v *gocui.View) error {
+		return func(g *gocui.Gui, v *gocui.View) error {
+			ran <- action
+			return nil
+		}
+	}
+
+	press := keyPress{'g', gocui.ModNone}
+	root := newKeySequenceNode()
+	prefix := newKeySequenceNode()
+	prefix.action, prefix.fn = "move_to_page_first_row", record("move_to_page_first_row")
+	sequence := newKeySequenceNode()
+	sequence.action, sequence.fn = "first_page", record("first_page")
+	prefix.children[press] = sequence
+	root.children[press] = prefix
+
+	ct := &Cointop{State: &State{}}
+	ct.State.keySequences = map[string]*keySequenceDispatcher{
+		"table": {root: root, update: func(f func() error) { f() }},
+	}
+	fn := ct.keySequenceFn("table", press)
+
+	// the prefix waits for the next key, then runs its action on timeout
+	if err := fn(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case action := <-ran:
+		if action != "move_to_page_first_row" {
+			t.Errorf("got %s after the timeout, want move_to_page_first_row", action)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the prefix action didn't run after the timeout")
+	}
+
+	// the whole sequence runs right away and the prefix action doesn't run
+	fn(nil, nil)
+	fn(nil, nil)
+	select {
+	case action := <-ran:
+		if action != "first_page" {
+			t.Errorf("got %s, want first_page", action)
+		}
+	default:
+		t.Error("the sequence action didn't run")
+	}
+	time.Sleep(5 * keySequenceTimeout)
+	select {
+	case action := <-ran:
+		t.Errorf("got %s after the sequence, want nothing", action)
+	default:
+	}
+}
+
+func TestLoadShortcutsFromConfigWarnings(t *testing.T) {
+	ct := &Cointop{
+		ActionsMap: ActionsMap(),
+		State: &State{
+			shortcutKeys:       make(map[string]string),
+			scopedShortcutKeys: make(map[string]map[string]string),
+		},
+	}
+	ct.config.Shortcuts = map[string]interface{}{
+		"j":            "move_down",
+		"k":            "notanaction",
+		"ctrl+notakey": "move_up",
+		"chart":        map[string]interface{}{"x": "toggle_compare_chart", "y": 1},
+		"notascope":    map[string]interface{}{"x": "quit"},
+	}
+
+	if err := ct.loadShortcutsFromConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ct.State.shortcutKeys, map[string]string{"j": "move_down"}) {
+		t.Errorf("got shortcuts %v", ct.State.shortcutKeys)
+	}
+	if !reflect.DeepEqual(ct.State.scopedShortcutKeys, map[string]map[string]string{"chart": {"x": "toggle_compare_chart"}}) {
+		t.Errorf("got scoped shortcuts %v", ct.State.scopedShortcutKeys)
+	}
+	if len(ct.State.shortcutWarnings) != 4 {
+		t.Errorf("got warnings %q, want 4", ct.State.shortcutWarnings)
+	}
+}
@@ -11,6 +11,79 @@ import (
 
 var lastWidth int
 
+const (
+	// minTableRows is the number of table rows kept before the chart and the panels are shown
+	minTableRows = 10
+	// minChartHeight is the smallest chart height, below it the chart is hidden
+	minChartHeight = 5
+	// minChartWidth is the smallest terminal width the chart is shown at
+	minChartWidth = 40
+	// compactStatusbarWidth is the terminal width below which the statusbar hints are collapsed
+	compactStatusbarWidth = 120
+)
+
+// screenLayout is which views are shown, and how tall they are, for a terminal size
+type screenLayout struct {
+	Marketbar        bool
+	ChartHeight      int
+	VolumeHeight     int
+	IndicatorHeight  int
+	CompactStatusbar bool
+}
+
+// newScreenLayout returns the layout for the terminal size. The marketbar, the chart, the volume
+// bars and the indicator panel are shrunk or hidden in that order so the table keeps its rows.
+// Rows are only reserved for the marketbar and the statusbar when they aren't hidden
+func newScreenLayout(width, height, chartHeight, volumeHeight, indicatorHeight int, hideMarketbar, hideStatusbar bool) screenLayout {
+	layout := screenLayout{
+		CompactStatusbar: width < compactStatusbarWidth,
+	}
+
+	// NOTE: the table header and the statusbar take a row each
+	rows := height - 1 - minTableRows
+	if !hideStatusbar {
+		rows--
+	}
+	if !hideMarketbar {
+		if rows < 1 {
+			return layout
+		}
+		layout.Marketbar = true
+		rows--
+	}
+
+	if width < minChartWidth || chartHeight == 0 {
+		return layout
+	}
+	if chartHeight > rows {
+		chartHeight = rows
+	}
+	if chartHeight < minChartHeight {
+		return layout
+	}
+	layout.ChartHeight = chartHeight
+	rows -= chartHeight
+
+	if volumeHeight > 0 && volumeHeight <= rows {
+		layout.VolumeHeight = volumeHeight
+		rows -= volumeHeight
+	}
+	if indicatorHeight > 0 && indicatorHeight <= rows {
+		layout.IndicatorHeight = indicatorHeight
+	}
+
+	return layout
+}
+
+// chartHeight returns the height the chart is drawn at for the terminal size
+func (ct *Cointop) chartHeight() int {
+	if ct.State.screenLayout.ChartHeight > 0 {
+		return ct.State.screenLayout.ChartHeight
+	}
+
+	return ct.State.chartHeight
+}
+
 // layout sets initial layout
 func (ct *Cointop) layout(g *gocui.Gui) error {
 	ct.debuglog("layout()")
@@ -22,6 +95,11 @@ func (ct *Cointop) layout(g *gocui.Gui) error {
 	headerHeight := 1
 	marketbarHeight := 1
 	chartHeight := ct.State.chartHeight
+	volumeHeight := ct.State.volumeHeight
+	indicatorPanelHeight := 0
+	if ct.State.indicatorPanel != "" {
+		indicatorPanelHeight = indicatorHeight
+	}
 	statusbarHeight := 1
 
 	if ct.State.onlyTable {
@@ -36,13 +114,28 @@ func (ct *Cointop) layout(g *gocui.Gui) error {
 
 	if ct.State.hideChart {
 		chartHeight = 0
+		volumeHeight = 0
+		indicatorPanelHeight = 0
 	}
 
 	if ct.State.hideStatusbar {
 		statusbarHeight = 0
 	}
 
-	if !ct.State.hideMarketbar {
+	screen := newScreenLayout(ct.width(), maxY, chartHeight, volumeHeight, indicatorPanelHeight, ct.State.hideMarketbar, ct.State.hideStatusbar)
+	if !screen.Marketbar {
+		marketbarHeight = 0
+	}
+	chartHeight = screen.ChartHeight
+	volumeHeight = screen.VolumeHeight
+	indicatorPanelHeight = screen.IndicatorHeight
+	if screen != ct.State.screenLayout {
+		ct.State.screenLayout = screen
+		go ct.UpdateChart()
+		go ct.UpdateStatusbar("")
+	}
+
+	if marketbarHeight > 0 {
 		if v, err := g.SetView(ct.Views.Marketbar.Name(), 0, topOffset, maxX, 2); err != nil {
 			if err != gocui.ErrUnknownView {
 				return err
@@ -70,7 +163,7 @@ func (ct *Cointop) layout(g *gocui.Gui) error {
 
 	topOffset = topOffset + marketbarHeight
 
-	if !ct.State.hideChart {
+	if chartHeight > 0 {
 		if v, err := g.SetView(ct.Views.Chart.Name(), 0, topOffset, maxX, topOffset+chartHeight+marketbarHeight); err != nil {
 			if err != gocui.ErrUnknownView {
 				return err
@@ -99,6 +192,50 @@ func (ct *Cointop) layout(g *gocui.Gui) error {
 	}
 
 	topOffset = topOffset + chartHeight
+
+	if volumeHeight > 0 {
+		if v, err := g.SetView(ct.Views.Volume.Name(), 0, topOffset, maxX, topOffset+volumeHeight+1); err != nil {
+			if err != gocui.ErrUnknownView {
+				return err
+			}
+			v.Clear()
+			ct.Views.Volume.SetBacking(v)
+			ct.Views.Volume.Backing().Frame = false
+			ct.colorscheme.SetViewColor(ct.Views.Volume.Backing(), "chart")
+			go ct.UpdateChart()
+		}
+	} else {
+		if ct.Views.Volume.Backing() != nil {
+			if err := g.DeleteView(ct.Views.Volume.Name()); err != nil {
+				return err
+			}
+			ct.Views.Volume.SetBacking(nil)
+		}
+	}
+
+	topOffset = topOffset + volumeHeight
+
+	if indicatorPanelHeight > 0 {
+		if v, err := g.SetView(ct.Views.Indicator.Name(), 0, topOffset, maxX, topOffset+indicatorPanelHeight+1); err != nil {
+			if err != gocui.ErrUnknownView {
+				return err
+			}
+			v.Clear()
+			ct.Views.Indicator.SetBacking(v)
+			ct.Views.Indicator.Backing().Frame = false
+			ct.colorscheme.SetViewColor(ct.Views.Indicator.Backing(), "chart")
+			go ct.UpdateChart()
+		}
+	} else {
+		if ct.Views.Indicator.Backing() != nil {
+			if err := g.DeleteView(ct.Views.Indicator.Name()); err != nil {
+				return err
+			}
+			ct.Views.Indicator.SetBacking(nil)
+		}
+	}
+
+	topOffset = topOffset + indicatorPanelHeight
 	if v, err := g.SetView(ct.Views.TableHeader.Name(), 0, topOffset, ct.maxTableWidth, topOffset+2); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
@@ -156,6 +293,38 @@ func (ct *Cointop) layout(g *gocui.Gui) error {
 		ct.Views.SearchField.Backing().Wrap = true
 		ct.Views.SearchField.Backing().Frame = false
 		ct.colorscheme.SetViewColor(ct.Views.SearchField.Backing(), "searchbar")
+		ct.Views.SearchField.Backing().Editor = gocui.EditorFunc(ct.searchFieldEditor)
+	}
+
+	if v, err := g.SetView(ct.Views.SearchResults.Name(), 0, maxY-searchResultsHeight-4, ct.maxTableWidth/2, maxY-2); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ct.Views.SearchResults.SetBacking(v)
+		ct.Views.SearchResults.Backing().Frame = true
+		ct.colorscheme.SetViewColor(ct.Views.SearchResults.Backing(), "menu")
+	}
+
+	if v, err := g.SetView(ct.Views.FilterField.Name(), 0, maxY-2, ct.maxTableWidth, maxY); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ct.Views.FilterField.SetBacking(v)
+		ct.Views.FilterField.Backing().Editable = true
+		ct.Views.FilterField.Backing().Wrap = true
+		ct.Views.FilterField.Backing().Frame = false
+		ct.colorscheme.SetViewColor(ct.Views.FilterField.Backing(), "searchbar")
+	}
+
+	if v, err := g.SetView(ct.Views.CommandField.Name(), 0, maxY-2, ct.maxTableWidth, maxY); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ct.Views.CommandField.SetBacking(v)
+		ct.Views.CommandField.Backing().Editable = true
+		ct.Views.CommandField.Backing().Wrap = true
+		ct.Views.CommandField.Backing().Frame = false
+		ct.colorscheme.SetViewColor(ct.Views.CommandField.Backing(), "searchbar")
 	}
 
 	if v, err := g.SetView(ct.Views.Help.Name(), 1, 1, ct.maxTableWidth-1, maxY-1); err != nil {
@@ -164,6 +333,8 @@ func (ct *Cointop) layout(g *gocui.Gui) error {
 		}
 		ct.Views.Help.SetBacking(v)
 		ct.Views.Help.Backing().Frame = false
+		ct.Views.Help.Backing().Editable = true
+		ct.Views.Help.Backing().Editor = gocui.EditorFunc(ct.helpEditor)
 		ct.colorscheme.SetViewColor(ct.Views.Help.Backing(), "menu")
 	}
 
@@ -194,17 +365,83 @@ func (ct *Cointop) layout(g *gocui.Gui) error {
 		ct.Views.ConvertMenu.SetBacking(v)
 		ct.Views.ConvertMenu.Backing().Frame = false
 		ct.colorscheme.SetViewColor(ct.Views.ConvertMenu.Backing(), "menu")
+	}
+
+	if v, err := g.SetView(ct.Views.WatchlistsMenu.Name(), 1, 1, ct.maxTableWidth-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ct.Views.WatchlistsMenu.SetBacking(v)
+		ct.Views.WatchlistsMenu.Backing().Frame = false
+		ct.colorscheme.SetViewColor(ct.Views.WatchlistsMenu.Backing(), "menu")
+	}
+
+	if v, err := g.SetView(ct.Views.ColorschemeMenu.Name(), 1, 1, ct.maxTableWidth-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ct.Views.ColorschemeMenu.SetBacking(v)
+		ct.Views.ColorschemeMenu.Backing().Frame = false
+		ct.colorscheme.SetViewColor(ct.Views.ColorschemeMenu.Backing(), "menu")
+	}
+
+	if v, err := g.SetView(ct.Views.TopMovers.Name(), 1, 1, ct.maxTableWidth-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ct.Views.TopMovers.SetBacking(v)
+		ct.Views.TopMovers.Backing().Frame = false
+		ct.colorscheme.SetViewColor(ct.Views.TopMovers.Backing(), "menu")
+	}
+
+	if v, err := g.SetView(ct.Views.Calculator.Name(), 1, 1, ct.maxTableWidth-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ct.Views.Calculator.SetBacking(v)
+		ct.Views.Calculator.Backing().Frame = false
+		ct.colorscheme.SetViewColor(ct.Views.Calculator.Backing(), "menu")
+	}
+
+	if v, err := g.SetView(ct.Views.CalculatorInput.Name(), 3, 6, 3+calculatorInputWidth, 8); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ct.Views.CalculatorInput.SetBacking(v)
+		ct.Views.CalculatorInput.Backing().Frame = true
+		ct.Views.CalculatorInput.Backing().Editable = true
+		ct.Views.CalculatorInput.Backing().Editor = gocui.EditorFunc(ct.calculatorEditor)
+		ct.colorscheme.SetViewColor(ct.Views.CalculatorInput.Backing(), "menu")
+	}
+
+	if v, err := g.SetView(ct.Views.TableColumnsMenu.Name(), 1, 1, ct.maxTableWidth-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		ct.Views.TableColumnsMenu.SetBacking(v)
+		ct.Views.TableColumnsMenu.Backing().Frame = false
+		ct.colorscheme.SetViewColor(ct.Views.TableColumnsMenu.Backing(), "menu")
 
 		// run only once on init.
 		// this bit of code should be at the bottom
 		ct.g = g
 		g.SetViewOnBottom(ct.Views.SearchField.Name())         // hide
+		g.SetViewOnBottom(ct.Views.FilterField.Name())         // hide
+		g.SetViewOnBottom(ct.Views.CommandField.Name())        // hide
+		g.SetViewOnBottom(ct.Views.SearchResults.Name())       // hide
 		g.SetViewOnBottom(ct.Views.Help.Name())                // hide
 		g.SetViewOnBottom(ct.Views.ConvertMenu.Name())         // hide
 		g.SetViewOnBottom(ct.Views.PortfolioUpdateMenu.Name()) // hide
 		g.SetViewOnBottom(ct.Views.Input.Name())               // hide
+		g.SetViewOnBottom(ct.Views.TableColumnsMenu.Name())    // hide
+		g.SetViewOnBottom(ct.Views.WatchlistsMenu.Name())      // hide
+		g.SetViewOnBottom(ct.Views.ColorschemeMenu.Name())     // hide
+		g.SetViewOnBottom(ct.Views.TopMovers.Name())           // hide
+		g.SetViewOnBottom(ct.Views.Calculator.Name())          // hide
+		g.SetViewOnBottom(ct.Views.CalculatorInput.Name())     // hide
 		ct.SetActiveView(ct.Views.Table.Name())
 		ct.intervalFetchData()
+		ct.watchColorscheme()
 	}
 
 	if lastWidth != maxX {
@@ -212,5 +449,8 @@ func (ct *Cointop) layout(g *gocui.Gui) error {
 		ct.refresh()
 	}
 
+	// NOTE: the layout runs after every event
+	ct.saveTableCursor()
+
 	return nil
 }
@@ -0,0 +1,121 @@
+package cointop
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewScreenLayout(t *testing.T) {
+	tests := []struct {
+		width, height int
+		want          screenLayout
+	}{
+		{200, 60, screenLayout{Marketbar: true, ChartHeight: 10, VolumeHeight: 4, IndicatorHeight: 6}},
+		{120, 40, screenLayout{Marketbar: true, ChartHeight: 10, VolumeHeight: 4, IndicatorHeight: 6}},
+		{120, 30, screenLayout{Marketbar: true, ChartHeight: 10, VolumeHeight: 4}},
+		{100, 27, screenLayout{Marketbar: true, ChartHeight: 10, VolumeHeight: 4, CompactStatusbar: true}},
+		{80, 24, screenLayout{Marketbar: true, ChartHeight: 10, CompactStatusbar: true}},
+		{80, 20, screenLayout{Marketbar: true, ChartHeight: 7, CompactStatusbar: true}},
+		{60, 15, screenLayout{Marketbar: true, CompactStatusbar: true}},
+		{35, 40, screenLayout{Marketbar: true, CompactStatusbar: true}},
+		{40, 10, screenLayout{CompactStatusbar: true}},
+	}
+
+	for _, test := range tests {
+		got := newScreenLayout(test.width, test.height, 10, 4, indicatorHeight, false, false)
+		if got != test.want {
+			t.Errorf("newScreenLayout(%d, %d) = %+v, want %+v", test.width, test.height, got, test.want)
+		}
+	}
+}
+
+func TestNewScreenLayoutHiddenPanels(t *testing.T) {
+	got := newScreenLayout(200, 60, 0, 4, indicatorHeight, false, false)
+	want := screenLayout{Marketbar: true}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	got = newScreenLayout(200, 60, 30, 0, 0, false, false)
+	want = screenLayout{Marketbar: true, ChartHeight: 30}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestNewScreenLayoutHiddenBars(t *testing.T) {
+	tests := []struct {
+		height                       int
+		hideMarketbar, hideStatusbar bool
+		want                         screenLayout
+	}{
+		{20, false, false, screenLayout{Marketbar: true, ChartHeight: 7, CompactStatusbar: true}},
+		{20, true, false, screenLayout{ChartHeight: 8, CompactStatusbar: true}},
+		{20, false, true, screenLayout{Marketbar: true, ChartHeight: 8, CompactStatusbar: true}},
+		{20, true, true, screenLayout{ChartHeight: 9, CompactStatusbar: true}},
+		{16, false, false, screenLayout{Marketbar: true, CompactStatusbar: true}},
+		{16, true, true, screenLayout{ChartHeight: 5, CompactStatusbar: true}},
+		{12, false, true, screenLayout{Marketbar: true, CompactStatusbar: true}},
+	}
+
+	for _, test := range tests {
+		got := newScreenLayout(80, test.height, 10, 0, 0, test.hideMarketbar, test.hideStatusbar)
+		if got != test.want {
+			t.Errorf("newScreenLayout(80, %d) hiding the marketbar %v and the statusbar %v = %+v, want %+v", test.height, test.hideMarketbar, test.hideStatusbar, got, test.want)
+		}
+	}
+}
+
+func TestFitTableColumns(t *testing.T) {
+	ct := &Cointop{State: &State{}}
+	defs := ct.tableColumnDefs(0)
+	var cols []*TableColumn
+	for _, name := range ct.defaultTableColumns("coins") {
+		cols = append(cols, defs[name])
+	}
+
+	tests := []struct {
+		width    int
+		priority []string
+		want     []string
+	}{
+		{200, TableColumnPriority(), []string{"rank", "name", "symbol", "price", "marketcap", "24hvolume", "1hchange", "24hchange", "7dchange", "sparkline", "30dchange", "1ychange", "availablesupply", "lastupdated"}},
+		{120, TableColumnPriority(), []string{"rank", "name", "symbol", "price", "marketcap", "24hvolume", "1hchange", "24hchange", "7dchange"}},
+		{80, TableColumnPriority(), []string{"rank", "name", "symbol", "price", "24hchange"}},
+		{60, TableColumnPriority(), []string{"rank", "name", "price", "24hchange"}},
+		{40, TableColumnPriority(), []string{"rank", "name"}},
+		{1, TableColumnPriority(), []string{"rank"}},
+		{80, []string{"symbol", "price", "7dchange", "sparkline"}, []string{"rank", "name", "symbol", "price", "7dchange", "sparkline"}},
+		{80, []string{"name", "price"}, []string{"rank", "name", "symbol", "price", "marketcap"}},
+	}
+
+	for _, test := range tests {
+		var got []string
+		for _, col := range fitTableColumns(cols, test.width, test.priority) {
+			got = append(got, col.Name)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("fitTableColumns(%d, %v) = %v, want %v", test.width, test.priority, got, test.want)
+		}
+	}
+}
+
+func TestStatusbarHintText(t *testing.T) {
+	tests := []struct {
+		hint    statusbarHint
+		compact bool
+		want    string
+	}{
+		{statusbarHint{"[Q]Quit", "quit_view"}, false, "[Q]Quit"},
+		{statusbarHint{"[Q]Quit", "quit_view"}, true, "[Q]"},
+		{statusbarHint{"[?]Help", "help"}, true, "[?]Help"},
+		{statusbarHint{"[[ ]]Range", "next_chart_range"}, true, "[[ ]]"},
+		{statusbarHint{"[CTRL-S]Save", "save"}, true, "[CTRL-S]"},
+	}
+
+	for _, test := range tests {
+		if got := test.hint.text(test.compact); got != test.want {
+			t.Errorf("%q.text(%v) = %q, want %q", test.hint.Text, test.compact, got, test.want)
+		}
+	}
+}
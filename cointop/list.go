@@ -1,6 +1,8 @@
 package cointop
 
 import (
+	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -29,6 +31,11 @@ func (ct *Cointop) updateCoins() error {
 	// cache miss
 	if allCoinsSlugMap == nil {
 		ct.debuglog("cache miss")
+		if err := ct.updateSecondaryConversionRate(); err != nil {
+			ct.debuglog(err.Error())
+			go ct.UpdateStatusbar(fmt.Sprintf("Exchange rate error: %s", err))
+		}
+
 		ch := make(chan []types.Coin)
 		err = ct.api.GetAllCoinData(ct.State.currencyConversion, ch)
 		if err != nil {
@@ -66,21 +73,25 @@ func (ct *Cointop) processCoins(coins []types.Coin) {
 		k := v.Name
 		ilast, _ := ct.State.allCoinsSlugMap.Load(k)
 		ct.State.allCoinsSlugMap.Store(k, &Coin{
-			ID:               v.ID,
-			Name:             v.Name,
-			Symbol:           v.Symbol,
-			Rank:             v.Rank,
-			Price:            v.Price,
-			Volume24H:        v.Volume24H,
-			MarketCap:        v.MarketCap,
-			AvailableSupply:  v.AvailableSupply,
-			TotalSupply:      v.TotalSupply,
-			PercentChange1H:  v.PercentChange1H,
-			PercentChange24H: v.PercentChange24H,
-			PercentChange7D:  v.PercentChange7D,
-			PercentChange30D: v.PercentChange30D,
-			PercentChange1Y:  v.PercentChange1Y,			
-			LastUpdated:      v.LastUpdated,
+			ID:                 v.ID,
+			Name:               v.Name,
+			Symbol:             v.Symbol,
+			Rank:               v.Rank,
+			Price:              v.Price,
+			Volume24H:          v.Volume24H,
+			MarketCap:          v.MarketCap,
+			AvailableSupply:    v.AvailableSupply,
+			TotalSupply:        v.TotalSupply,
+			PercentChange1H:    v.PercentChange1H,
+			PercentChange24H:   v.PercentChange24H,
+			PercentChange7D:    v.PercentChange7D,
+			PercentChange30D:   v.PercentChange30D,
+			PercentChange1Y:    v.PercentChange1Y,
+			LastUpdated:        v.LastUpdated,
+			Currency:           ct.State.currencyConversion,
+			Sparkline7D:        v.Sparkline7D,
+			SecondaryPrice:     ct.toSecondaryPrice(v.Price),
+			SecondaryMarketCap: math.Floor(ct.toSecondaryCurrency(v.MarketCap)),
 		})
 		if ilast != nil {
 			last, _ := ilast.(*Coin)
@@ -100,6 +111,7 @@ func (ct *Cointop) processCoins(coins []types.Coin) {
 		return true
 	})
 
+	priceChanged := false
 	if len(ct.State.allCoins) < size {
 		list := []*Coin{}
 		for _, v := range coins {
@@ -116,6 +128,10 @@ func (ct *Cointop) processCoins(coins []types.Coin) {
 			for k := range ct.State.allCoins {
 				c := ct.State.allCoins[k]
 				if c.ID == cm.ID {
+					if trackPriceChange(c, cm) {
+						priceChanged = true
+					}
+					trackRankChange(c, cm)
 					// TODO: improve this
 					c.ID = cm.ID
 					c.Name = cm.Name
@@ -132,6 +148,10 @@ func (ct *Cointop) processCoins(coins []types.Coin) {
 					c.PercentChange30D = cm.PercentChange30D
 					c.PercentChange1Y = cm.PercentChange1Y
 					c.LastUpdated = cm.LastUpdated
+					c.Sparkline7D = cm.Sparkline7D
+					c.Currency = cm.Currency
+					c.SecondaryPrice = cm.SecondaryPrice
+					c.SecondaryMarketCap = cm.SecondaryMarketCap
 					c.Favorite = cm.Favorite
 				}
 			}
@@ -140,18 +160,32 @@ func (ct *Cointop) processCoins(coins []types.Coin) {
 		})
 	}
 
+	ct.updateRankHistory(coins)
+
 	time.AfterFunc(10*time.Millisecond, func() {
 		ct.sort(ct.State.sortBy, ct.State.sortDesc, ct.State.coins, true)
 		ct.UpdateTable()
+		if ct.State.topMoversVisible {
+			ct.updateTopMovers()
+		}
 	})
+
+	// NOTE: redraw the table when the changed prices are no longer highlighted
+	if priceChanged {
+		time.AfterFunc(priceFlashDuration, func() {
+			ct.UpdateTable()
+		})
+	}
 }
 
 func (ct *Cointop) getListCount() int {
 	ct.debuglog("getListCount()")
 	if ct.State.filterByFavorites {
-		return len(ct.State.favorites)
+		return len(ct.watchlist(ct.State.selectedWatchlist))
 	} else if ct.State.portfolioVisible {
 		return len(ct.State.portfolio.Entries)
+	} else if ct.State.filter != nil {
+		return len(ct.filterCoins(ct.State.allCoins))
 	} else {
 		return len(ct.State.allCoins)
 	}
@@ -0,0 +1,121 @@
+package cointop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cdyfng/coind/cointop/common/humanize"
+	"golang.org/x/text/language"
+)
+
+// currencySuffixLanguages are the languages which write the currency symbol after the amount, e.g. "1.234,56 €"
+var currencySuffixLanguages = map[string]bool{
+	"bg": true,
+	"cs": true,
+	"da": true,
+	"de": true,
+	"el": true,
+	"es": true,
+	"et": true,
+	"fi": true,
+	"fr": true,
+	"hr": true,
+	"hu": true,
+	"is": true,
+	"it": true,
+	"lt": true,
+	"lv": true,
+	"nb": true,
+	"pl": true,
+	"pt": true,
+	"ro": true,
+	"ru": true,
+	"sk": true,
+	"sl": true,
+	"sr": true,
+	"sv": true,
+	"tr": true,
+	"uk": true,
+	"vi": true,
+}
+
+// currencyPrefixRegions are the regions which write the currency symbol before the amount, separated by a
+// space, even though their language writes it after, e.g. "R$ 1.234,56" in pt-BR
+var currencyPrefixRegions = map[string]bool{
+	"de-AT": true,
+	"de-CH": true,
+	"it-CH": true,
+	"pt-BR": true,
+}
+
+// parseLocale parses a BCP 47 locale, e.g. "de-DE". The empty locale formats numbers the US way
+func parseLocale(s string) (language.Tag, error) {
+	s = strings.TrimSpace(strings.Replace(s, "_", "-", -1))
+	if s == "" {
+		return language.Und, nil
+	}
+
+	tag, err := language.Parse(s)
+	if err != nil {
+		return language.Und, fmt.Errorf("invalid locale %q", s)
+	}
+
+	return tag, nil
+}
+
+// formatNumber returns the number with the separators of the locale, keeping all of its decimals
+func (ct *Cointop) formatNumber(v float64) string {
+	if ct.State.locale == language.Und {
+		return humanize.Commaf(v)
+	}
+
+	return humanize.Localef(v, ct.State.locale)
+}
+
+// formatNumberN returns the number rounded to the decimals with the separators of the locale
+func (ct *Cointop) formatNumberN(v float64, decimals int) string {
+	tag := ct.State.locale
+	if tag == language.Und {
+		tag = language.English
+	}
+
+	return humanize.LocalefN(v, decimals, tag)
+}
+
+// formatCompact returns the number in the compact format, e.g. 1.23T, with the decimal separator of the locale
+func (ct *Cointop) formatCompact(v float64) string {
+	tag := ct.State.locale
+	if tag == language.Und {
+		tag = language.English
+	}
+
+	return humanize.LocaleCompactf(v, ct.State.compactPrecision, tag)
+}
+
+// formatPercent returns the percentage with two decimals and the decimal separator of the locale
+func (ct *Cointop) formatPercent(v float64) string {
+	if ct.State.locale == language.Und {
+		return fmt.Sprintf("%.2f%%", v)
+	}
+
+	return fmt.Sprintf("%s%%", humanize.LocalefN(v, 2, ct.State.locale))
+}
+
+// formatCurrency places the currency symbol before or after the amount, the way the locale writes it
+func (ct *Cointop) formatCurrency(symbol string, amount string) string {
+	tag := ct.State.locale
+	if tag == language.Und {
+		return fmt.Sprintf("%s%s", symbol, amount)
+	}
+
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	if currencyPrefixRegions[fmt.Sprintf("%s-%s", base, region)] {
+		return fmt.Sprintf("%s %s", symbol, amount)
+	}
+	if currencySuffixLanguages[base.String()] {
+		return fmt.Sprintf("%s %s", amount, symbol)
+	}
+
+	return fmt.Sprintf("%s%s", symbol, amount)
+}
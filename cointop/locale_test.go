@@ -0,0 +1,42 @@
+package cointop
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		locale string
+		symbol string
+		amount string
+		want   string
+	}{
+		{"", "$", "1,234.56", "$1,234.56"},
+		{"en-US", "$", "1,234.56", "$1,234.56"},
+		{"de-DE", "€", "1.234,56", "1.234,56 €"},
+		{"de_DE", "€", "1.234,56", "1.234,56 €"},
+		{"fr", "€", "1 234,56", "1 234,56 €"},
+		{"pt-BR", "R$", "1.234,56", "R$ 1.234,56"},
+		{"pt-PT", "€", "1.234,56", "1.234,56 €"},
+		{"de-CH", "CHF", "1’234.56", "CHF 1’234.56"},
+	}
+
+	for _, test := range tests {
+		tag, err := parseLocale(test.locale)
+		if err != nil {
+			t.Errorf("parseLocale(%q) error: %v", test.locale, err)
+			continue
+		}
+		ct := &Cointop{State: &State{locale: tag}}
+		if got := ct.formatCurrency(test.symbol, test.amount); got != test.want {
+			t.Errorf("formatCurrency(%q, %q) in %q = %q, want %q", test.symbol, test.amount, test.locale, got, test.want)
+		}
+	}
+
+	ct := &Cointop{State: &State{locale: language.Und}}
+	if got, want := ct.formatCurrency("€", "1,234.56"), "€1,234.56"; got != want {
+		t.Errorf("formatCurrency() in the undefined locale = %q, want %q", got, want)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	types "github.com/cdyfng/coind/cointop/common/api/types"
 	"github.com/cdyfng/coind/cointop/common/color"
 	"github.com/cdyfng/coind/cointop/common/filecache"
-	"github.com/cdyfng/coind/cointop/common/humanize"
 	"github.com/cdyfng/coind/cointop/common/pad"
 )
 
@@ -37,10 +36,10 @@ func (ct *Cointop) updateMarketbar() error {
 
 	if ct.State.portfolioVisible {
 		total := ct.getPortfolioTotal()
-		totalstr := humanize.Commaf(total)
+		totalstr := ct.formatNumber(total)
 		if !(ct.State.currencyConversion == "BTC" || ct.State.currencyConversion == "ETH" || total < 1) {
 			total = math.Round(total*1e2) / 1e2
-			totalstr = humanize.Commaf2(total)
+			totalstr = ct.formatNumberN(total, 2)
 		}
 
 		timeframe := ct.State.selectedChartRange
@@ -82,11 +81,21 @@ func (ct *Cointop) updateMarketbar() error {
 			)
 		}
 
+		totalValue := ct.colorscheme.MarketBarLabelActive(ct.formatCurrency(ct.currencySymbol(), totalstr))
+		if ct.State.secondaryCurrencyConversion != "" && ct.State.secondaryConversionRate != 0 {
+			secondaryTotal := math.Round(ct.toSecondaryCurrency(total)*1e2) / 1e2
+			totalValue = fmt.Sprintf(
+				"%s (%s)",
+				totalValue,
+				ct.colorscheme.MarketBarLabelActive(ct.formatCurrency(ct.secondaryCurrencySymbol(), ct.formatNumberN(secondaryTotal, 2))),
+			)
+		}
+
 		content = fmt.Sprintf(
 			"%sTotal Portfolio Value: %s • 24H: %s",
 			chartInfo,
-			ct.colorscheme.MarketBarLabelActive(fmt.Sprintf("%s%s", ct.currencySymbol(), totalstr)),
-			color24h(fmt.Sprintf("%.2f%%%s", percentChange24H, arrow)),
+			totalValue,
+			color24h(fmt.Sprintf("%s%s", ct.formatPercent(percentChange24H), arrow)),
 		)
 	} else {
 		var market types.GlobalMarketData
@@ -131,11 +140,11 @@ func (ct *Cointop) updateMarketbar() error {
 		}
 
 		content = fmt.Sprintf(
-			"%sGlobal ▶ Market Cap: %s • 24H Volume: %s • BTC Dominance: %.2f%%",
+			"%sGlobal ▶ Market Cap: %s • 24H Volume: %s • BTC Dominance: %s",
 			chartInfo,
-			fmt.Sprintf("%s%s", ct.currencySymbol(), humanize.Commaf0(market.TotalMarketCapUSD)),
-			fmt.Sprintf("%s%s", ct.currencySymbol(), humanize.Commaf0(market.Total24HVolumeUSD)),
-			market.BitcoinPercentageOfMarketCap,
+			ct.formatCurrency(ct.currencySymbol(), ct.formatNumberN(market.TotalMarketCapUSD, 0)),
+			ct.formatCurrency(ct.currencySymbol(), ct.formatNumberN(market.Total24HVolumeUSD, 0)),
+			ct.formatPercent(market.BitcoinPercentageOfMarketCap),
 		)
 	}
 
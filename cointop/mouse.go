@@ -0,0 +1,137 @@
+package cointop
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// NOTE: gocui moves the cursor of the view under the mouse to the clicked cell
+// before it calls the mouse handlers of the view
+
+// mouseTableClick highlights the clicked row and shows its chart
+func (ct *Cointop) mouseTableClick() error {
+	ct.debuglog("mouseTableClick()")
+	if ct.Views.Table.Backing() == nil || len(ct.State.coins) == 0 {
+		return nil
+	}
+
+	// NOTE: clicks below the last row highlight the last row
+	_, oy := ct.Views.Table.Backing().Origin()
+	cx, cy := ct.Views.Table.Backing().Cursor()
+	if oy+cy >= len(ct.State.coins) {
+		ct.Views.Table.Backing().SetCursor(cx, len(ct.State.coins)-1-oy)
+	}
+	if ct.State.chartInspectVisible {
+		ct.hideChartInspect()
+	}
+	ct.RowChanged()
+
+	coin := ct.HighlightedRowCoin()
+	if coin == nil || coin == ct.State.selectedCoin {
+		return nil
+	}
+	ct.State.selectedCoin = coin
+
+	go func() {
+		// keep these two synchronous to avoid race conditions
+		ct.ShowChartLoader()
+		ct.UpdateChart()
+	}()
+
+	go ct.updateMarketbar()
+
+	return nil
+}
+
+// mouseTableWheelFn returns a function that moves the highlighted row of the table down, or up if negative
+func (ct *Cointop) mouseTableWheelFn(delta int) func() error {
+	return func() error {
+		ct.debuglog("mouseTableWheelFn()")
+		if ct.Views.Table.Backing() == nil {
+			return nil
+		}
+
+		// NOTE: the wheel doesn't highlight the row under the mouse so the cursor is restored first
+		cx, _ := ct.Views.Table.Backing().Cursor()
+		ct.Views.Table.Backing().SetCursor(cx, ct.State.tableCursorY)
+		if delta > 0 {
+			return ct.cursorDown()
+		}
+
+		return ct.cursorUp()
+	}
+}
+
+// saveTableCursor saves the table cursor so it can be restored after mouse wheel events
+func (ct *Cointop) saveTableCursor() {
+	if ct.Views.Table.Backing() == nil {
+		return
+	}
+
+	_, ct.State.tableCursorY = ct.Views.Table.Backing().Cursor()
+}
+
+// mouseHeaderClick sorts the table by the clicked column, or flips the sort direction if it's already sorted by it
+func (ct *Cointop) mouseHeaderClick() error {
+	ct.debuglog("mouseHeaderClick()")
+	if ct.Views.TableHeader.Backing() == nil {
+		return nil
+	}
+
+	ox, _ := ct.Views.TableHeader.Backing().Origin()
+	cx, _ := ct.Views.TableHeader.Backing().Cursor()
+	x := ox + cx
+	for _, col := range ct.visibleTableColumns(0) {
+		// NOTE: each column is followed by a space
+		x -= col.Width + 1
+		if x >= 0 {
+			continue
+		}
+
+		desc := true
+		switch col.Name {
+		case "rank", "name", "symbol":
+			desc = false
+		}
+
+		return ct.sortToggle(col.Name, desc)
+	}
+
+	return nil
+}
+
+// mouseStatusbarClick runs the action of the clicked statusbar hint
+func (ct *Cointop) mouseStatusbarClick() error {
+	ct.debuglog("mouseStatusbarClick()")
+	if ct.Views.Statusbar.Backing() == nil {
+		return nil
+	}
+
+	line, err := ct.Views.Statusbar.Backing().Line(0)
+	if err != nil {
+		return nil
+	}
+
+	cx, _ := ct.Views.Statusbar.Backing().Cursor()
+	hints := append(ct.statusbarHints(),
+		statusbarHint{"[← →]Page", "next_page"},
+		statusbarHint{"[|]Filter", "open_filter"},
+		statusbarHint{"[O]Open", "open_link"},
+	)
+	compact := ct.State.screenLayout.CompactStatusbar
+	for _, hint := range hints {
+		text := hint.text(compact)
+		i := strings.Index(line, text)
+		if i < 0 {
+			continue
+		}
+
+		start := utf8.RuneCountInString(line[:i])
+		if cx >= start && cx < start+utf8.RuneCountInString(text) {
+			fn, _ := ct.actionHandler(hint.Action, nil)
+			return fn(ct.g, ct.Views.Statusbar.Backing())
+		}
+	}
+
+	return nil
+}
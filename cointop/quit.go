@@ -1,6 +1,7 @@
 package cointop
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/miguelmota/gocui"
@@ -8,6 +9,10 @@ import (
 
 // Quit quites the program
 func (ct *Cointop) Quit() error {
+	if err := ct.saveSession(); err != nil {
+		ct.debuglog(fmt.Sprintf("session error: %s", err))
+	}
+
 	return gocui.ErrQuit
 }
 
@@ -32,6 +37,10 @@ func (ct *Cointop) QuitView() error {
 // Exit safely exits the program
 func (ct *Cointop) Exit() {
 	ct.debuglog("exit()")
+	if err := ct.saveSession(); err != nil {
+		ct.debuglog(fmt.Sprintf("session error: %s", err))
+	}
+
 	if ct.g != nil {
 		ct.g.Close()
 	} else {
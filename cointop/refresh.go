@@ -19,6 +19,7 @@ func (ct *Cointop) refreshAll() error {
 	ct.refreshMux.Lock()
 	defer ct.refreshMux.Unlock()
 	ct.setRefreshStatus()
+	ct.State.lastRefresh = time.Now()
 	ct.cache.Delete("allCoinsSlugMap")
 	ct.cache.Delete("market")
 	go func() {
@@ -1,10 +1,13 @@
 package cointop
 
 import (
-	"regexp"
+	"fmt"
+	"sort"
 	"strings"
 
-	"github.com/cdyfng/coind/cointop/common/levenshtein"
+	color "github.com/cdyfng/coind/cointop/common/color"
+	"github.com/cdyfng/coind/cointop/common/fuzzy"
+	"github.com/miguelmota/gocui"
 )
 
 // SearchFieldView is structure for search field view
@@ -17,6 +20,21 @@ func NewSearchFieldView() *SearchFieldView {
 	return &SearchFieldView{NewView("searchfield")}
 }
 
+// SearchResultsView is structure for search results view
+type SearchResultsView struct {
+	*View
+}
+
+// NewSearchResultsView returns a new search results view
+func NewSearchResultsView() *SearchResultsView {
+	return &SearchResultsView{NewView("searchresults")}
+}
+
+const (
+	maxSearchResults    = 50
+	searchResultsHeight = 10
+)
+
 // InputView is structure for help view
 type InputView struct {
 	*View
@@ -37,81 +55,223 @@ func (ct *Cointop) openSearch() error {
 func (ct *Cointop) cancelSearch() error {
 	ct.debuglog("cancelSearch()")
 	ct.State.searchFieldVisible = false
+	ct.State.searchMatches = nil
+	ct.hideSearchResults()
 	ct.SetActiveView(ct.Views.Table.Name())
 	return nil
 }
 
 func (ct *Cointop) doSearch() error {
 	ct.debuglog("doSearch()")
-	ct.Views.SearchField.Backing().Rewind()
-	b := make([]byte, 100)
-	n, err := ct.Views.SearchField.Backing().Read(b)
 
 	// TODO: do this a better way (SoC)
 	ct.State.filterByFavorites = false
 	ct.State.portfolioVisible = false
 
 	defer ct.SetActiveView(ct.Views.Table.Name())
-	if err != nil {
-		return nil
-	}
-	if n == 0 {
+	defer ct.hideSearchResults()
+	q := ct.searchQuery()
+	if q == "" {
 		return nil
 	}
-	q := string(b)
-	// remove slash
-	regex := regexp.MustCompile(`/(.*)`)
-	matches := regex.FindStringSubmatch(q)
-	if len(matches) > 0 {
-		q = matches[1]
+	if len(ct.State.searchResults) == 0 {
+		return ct.search(q)
 	}
-	return ct.search(q)
+
+	ct.State.searchMatches = ct.State.searchResults
+	ct.State.searchMatchIndex = ct.State.searchResultIndex
+	ct.State.searchMatchTable = ct.searchMatchTable()
+	return ct.goToSearchMatch()
+}
+
+// searchQuery returns the query in the search field without the slash
+func (ct *Cointop) searchQuery() string {
+	q := ct.Views.SearchField.Backing().Buffer()
+	q = strings.TrimSpace(q)
+	q = strings.TrimPrefix(q, "/")
+	return strings.TrimSpace(q)
 }
 
 func (ct *Cointop) search(q string) error {
 	ct.debuglog("search()")
+	ct.State.searchMatches = ct.rankSearchResults(q)
+	ct.State.searchMatchIndex = 0
+	ct.State.searchMatchTable = ct.searchMatchTable()
+	return ct.goToSearchMatch()
+}
+
+// rankSearchResults returns the coins matching the query, ordered by the fuzzy score of the symbol, name or ID
+func (ct *Cointop) rankSearchResults(q string) []*Coin {
+	ct.debuglog("rankSearchResults()")
 	q = strings.TrimSpace(strings.ToLower(q))
-	idx := -1
-	min := -1
-	var hasprefixidx []int
-	var hasprefixdist []int
-	for i := range ct.State.allCoins {
-		coin := ct.State.allCoins[i]
-		name := strings.ToLower(coin.Name)
-		symbol := strings.ToLower(coin.Symbol)
-		// if query matches symbol, return immediately
-		if symbol == q {
-			ct.goToGlobalIndex(i)
-			return nil
+	if q == "" {
+		return nil
+	}
+
+	type result struct {
+		coin  *Coin
+		score int
+	}
+	var results []result
+	for _, coin := range ct.filterCoins(ct.State.allCoins) {
+		if coin == nil {
+			continue
 		}
-		// if query matches name, return immediately
-		if name == q {
-			ct.goToGlobalIndex(i)
-			return nil
+		score := -1
+		// NOTE: symbols are short, so symbol matches are weighted higher
+		if s := fuzzy.Score(q, coin.Symbol); s >= 0 {
+			score = s * 2
+		}
+		if s := fuzzy.Score(q, coin.Name); s > score {
+			score = s
+		}
+		if s := fuzzy.Score(q, coin.ID); s > score {
+			score = s
 		}
-		// store index with the smallest levenshtein
-		dist := levenshtein.DamerauLevenshteinDistance(name, q)
-		if min == -1 || dist <= min {
-			idx = i
-			min = dist
+		if score < 0 {
+			continue
 		}
-		// store index where query is substring to name
-		if strings.HasPrefix(name, q) {
-			if len(hasprefixdist) == 0 || dist < hasprefixdist[0] {
-				hasprefixidx = append(hasprefixidx, i)
-				hasprefixdist = append(hasprefixdist, dist)
-			}
+		results = append(results, result{coin, score})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score == results[j].score {
+			return results[i].coin.Rank < results[j].coin.Rank
+		}
+		return results[i].score > results[j].score
+	})
+
+	if len(results) > maxSearchResults {
+		results = results[:maxSearchResults]
+	}
+
+	coins := make([]*Coin, len(results))
+	for i, r := range results {
+		coins[i] = r.coin
+	}
+
+	return coins
+}
+
+// searchFieldEditor updates the search results as the query is typed
+func (ct *Cointop) searchFieldEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+	ct.State.searchResults = ct.rankSearchResults(ct.searchQuery())
+	ct.State.searchResultIndex = 0
+	ct.updateSearchResults()
+}
+
+// updateSearchResults renders the search results dropdown
+func (ct *Cointop) updateSearchResults() {
+	ct.debuglog("updateSearchResults()")
+	results := ct.State.searchResults
+	if len(results) == 0 {
+		ct.hideSearchResults()
+		return
+	}
+
+	// NOTE: scroll the results so the selected result is always visible
+	start := 0
+	if ct.State.searchResultIndex >= searchResultsHeight {
+		start = ct.State.searchResultIndex - searchResultsHeight + 1
+	}
+	end := start + searchResultsHeight
+	if end > len(results) {
+		end = len(results)
+	}
+
+	var body string
+	for i := start; i < end; i++ {
+		coin := results[i]
+		item := fmt.Sprintf(" %-8s %-30s %6v ", coin.Symbol, coin.Name, coin.Rank)
+		if i == ct.State.searchResultIndex {
+			item = ct.colorscheme.MenuLabelActive(color.Bold(item))
+		} else {
+			item = ct.colorscheme.MenuLabel(item)
 		}
+		body = fmt.Sprintf("%s%s\n", body, item)
 	}
-	// go to row if prefix match
-	if len(hasprefixidx) > 0 && hasprefixidx[0] != -1 && min > 0 {
-		ct.goToGlobalIndex(hasprefixidx[0])
+
+	ct.g.SetViewOnTop(ct.Views.SearchResults.Name())
+	ct.Update(func() error {
+		if ct.Views.SearchResults.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.SearchResults.Backing().Clear()
+		fmt.Fprint(ct.Views.SearchResults.Backing(), body)
+		return nil
+	})
+}
+
+func (ct *Cointop) hideSearchResults() error {
+	ct.debuglog("hideSearchResults()")
+	ct.State.searchResults = nil
+	ct.State.searchResultIndex = 0
+	ct.SetViewOnBottom(ct.Views.SearchResults.Name())
+	return nil
+}
+
+// searchResultsCursorFn returns a function which moves the search results selection by the given offset
+func (ct *Cointop) searchResultsCursorFn(offset int) func() error {
+	return func() error {
+		ct.debuglog("searchResultsCursor()")
+		n := len(ct.State.searchResults)
+		if n == 0 {
+			return nil
+		}
+		ct.State.searchResultIndex = (ct.State.searchResultIndex + offset + n) % n
+		ct.updateSearchResults()
 		return nil
 	}
-	// go to row if levenshtein distance is small enough
-	if idx > -1 && min <= 6 {
-		ct.goToGlobalIndex(idx)
+}
+
+// searchMatchTable returns the view and the sort of the table, which the search matches are cycled through
+func (ct *Cointop) searchMatchTable() string {
+	return fmt.Sprintf("%s %s %s %v", ct.currentTableView(), ct.State.selectedWatchlist, ct.State.sortBy, ct.State.sortDesc)
+}
+
+// hasSearchMatches returns true if there are search matches and the table wasn't switched to another view or
+// sorted since the search. The n key goes to the next match while there are matches, or else sorts by name
+func (ct *Cointop) hasSearchMatches() bool {
+	return len(ct.State.searchMatches) > 0 && ct.State.searchMatchTable == ct.searchMatchTable()
+}
+
+// goToSearchMatch goes to the table row of the current search match
+func (ct *Cointop) goToSearchMatch() error {
+	ct.debuglog("goToSearchMatch()")
+	if len(ct.State.searchMatches) == 0 {
 		return nil
 	}
+
+	match := ct.State.searchMatches[ct.State.searchMatchIndex]
+	for i, coin := range ct.filterCoins(ct.State.allCoins) {
+		if coin == match {
+			return ct.goToGlobalIndex(i)
+		}
+	}
+
 	return nil
 }
+
+// nextSearchMatch goes to the next search match
+func (ct *Cointop) nextSearchMatch() error {
+	ct.debuglog("nextSearchMatch()")
+	n := len(ct.State.searchMatches)
+	if n == 0 {
+		return nil
+	}
+	ct.State.searchMatchIndex = (ct.State.searchMatchIndex + 1) % n
+	return ct.goToSearchMatch()
+}
+
+// previousSearchMatch goes to the previous search match
+func (ct *Cointop) previousSearchMatch() error {
+	ct.debuglog("previousSearchMatch()")
+	n := len(ct.State.searchMatches)
+	if n == 0 {
+		return nil
+	}
+	ct.State.searchMatchIndex = (ct.State.searchMatchIndex - 1 + n) % n
+	return ct.goToSearchMatch()
+}
@@ -0,0 +1,138 @@
+package cointop
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+const sessionFilename = "session.toml"
+
+// session is the state of the UI which is saved on quit and restored on the next launch
+type session struct {
+	SortBy      string `toml:"sort_by"`
+	SortDesc    bool   `toml:"sort_desc"`
+	Page        int    `toml:"page"`
+	Coin        string `toml:"coin"`
+	ChartRange  string `toml:"chart_range"`
+	ChartHeight int    `toml:"chart_height"`
+	View        string `toml:"view"`
+	Watchlist   string `toml:"watchlist"`
+}
+
+// sessionPath returns the path of the session file, which is next to the config file
+func (ct *Cointop) sessionPath() string {
+	return filepath.Join(ct.configDirPath(), sessionFilename)
+}
+
+// currentSession returns the session of the current state
+func (ct *Cointop) currentSession() *session {
+	view := "default"
+	if ct.State.portfolioVisible {
+		view = "portfolio"
+	} else if ct.State.filterByFavorites {
+		view = "favorites"
+	}
+
+	return &session{
+		SortBy:      ct.State.sortBy,
+		SortDesc:    ct.State.sortDesc,
+		Page:        ct.State.page,
+		Coin:        ct.selectedCoinName(),
+		ChartRange:  ct.State.selectedChartRange,
+		ChartHeight: ct.State.chartHeight,
+		View:        view,
+		Watchlist:   ct.State.selectedWatchlist,
+	}
+}
+
+// saveSession writes the session file
+func (ct *Cointop) saveSession() error {
+	ct.debuglog("saveSession()")
+	if err := ct.makeConfigDir(); err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := toml.NewEncoder(&b).Encode(ct.currentSession()); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(ct.sessionPath(), b.Bytes(), fileperm)
+}
+
+// loadSession reads the session file of the previous launch. The values which are no longer valid keep
+// their defaults
+func (ct *Cointop) loadSession() error {
+	ct.debuglog("loadSession()")
+	var s session
+	if _, err := toml.DecodeFile(ct.sessionPath(), &s); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("invalid session file %s: %v", ct.sessionPath(), err)
+	}
+
+	ct.restoreSession(&s)
+	return nil
+}
+
+// restoreSession sets the state from the session
+func (ct *Cointop) restoreSession(s *session) {
+	ct.debuglog("restoreSession()")
+	if _, ok := ct.tableColumnDefs(0)[s.SortBy]; ok {
+		ct.State.sortBy = s.SortBy
+		ct.State.sortDesc = s.SortDesc
+	}
+	if _, ok := ct.chartRangesMap[s.ChartRange]; ok {
+		ct.State.selectedChartRange = s.ChartRange
+	}
+	if s.ChartHeight >= 5 && s.ChartHeight <= 30 {
+		ct.State.chartHeight = s.ChartHeight
+	}
+
+	switch s.View {
+	case "portfolio":
+		ct.State.portfolioVisible = true
+		ct.State.filterByFavorites = false
+	case "favorites":
+		ct.State.portfolioVisible = false
+		ct.State.filterByFavorites = true
+	case "default":
+		ct.State.portfolioVisible = false
+		ct.State.filterByFavorites = false
+	}
+	if _, ok := ct.State.watchlists[s.Watchlist]; ok || s.Watchlist == defaultWatchlist {
+		ct.State.selectedWatchlist = s.Watchlist
+	}
+
+	// NOTE: the coin and the page are only restored when the coins of the previous launch are cached.
+	// The row of the coin is highlighted once the table is rendered
+	ct.State.restoredCoin = s.Coin
+	for _, coin := range ct.State.allCoins {
+		if coin != nil && coin.Name == s.Coin {
+			ct.State.selectedCoin = coin
+			break
+		}
+	}
+	ct.setPage(s.Page)
+}
+
+// restoredCoinRow returns the table row of the coin of the session, or the row if it isn't on the page.
+// The coin is only looked up on the first render of the table
+func (ct *Cointop) restoredCoinRow(row int) int {
+	ct.debuglog("restoredCoinRow()")
+	name := ct.State.restoredCoin
+	ct.State.restoredCoin = ""
+	for i, coin := range ct.State.coins {
+		if coin != nil && coin.Name == name {
+			return i
+		}
+	}
+
+	return row
+}
@@ -0,0 +1,124 @@
+package cointop
+
+import "testing"
+
+// newSessionTestCointop returns a cointop with the state the session is restored into
+func newSessionTestCointop(coins []*Coin) *Cointop {
+	return &Cointop{
+		chartRangesMap: chartRangesMap(),
+		State: &State{
+			allCoins:           coins,
+			perPage:            2,
+			sortBy:             "rank",
+			selectedChartRange: "1Y",
+			chartHeight:        10,
+			selectedWatchlist:  defaultWatchlist,
+			favorites:          map[string]bool{"Bitcoin": true},
+			watchlists: map[string]map[string]bool{
+				"defi": {"Bitcoin": true, "Ethereum": true, "Solana": true},
+			},
+		},
+	}
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	coins := []*Coin{{Name: "Bitcoin", Rank: 1}, {Name: "Ethereum", Rank: 2}, {Name: "Solana", Rank: 3}}
+	ct := newSessionTestCointop(coins)
+	ct.State.sortBy = "price"
+	ct.State.sortDesc = true
+	ct.State.page = 1
+	ct.State.selectedCoin = coins[2]
+	ct.State.selectedChartRange = "7D"
+	ct.State.chartHeight = 20
+	ct.State.filterByFavorites = true
+	ct.State.selectedWatchlist = "defi"
+
+	s := ct.currentSession()
+	want := session{"price", true, 1, "Solana", "7D", 20, "favorites", "defi"}
+	if *s != want {
+		t.Fatalf("currentSession() = %+v, want %+v", *s, want)
+	}
+
+	restored := newSessionTestCointop(coins)
+	restored.restoreSession(s)
+	if got := *restored.currentSession(); got != want {
+		t.Errorf("restoreSession(%+v) restored %+v", want, got)
+	}
+	if restored.State.restoredCoin != "Solana" {
+		t.Errorf("restoredCoin = %q, want Solana", restored.State.restoredCoin)
+	}
+
+	// the row of the coin is highlighted on the first render only
+	restored.State.coins = coins[2:]
+	if row := restored.restoredCoinRow(5); row != 0 {
+		t.Errorf("restoredCoinRow() = %d, want 0", row)
+	}
+	if row := restored.restoredCoinRow(5); row != 5 {
+		t.Errorf("restoredCoinRow() after the first render = %d, want 5", row)
+	}
+}
+
+func TestSessionRoundTripFavorites(t *testing.T) {
+	coins := []*Coin{{Name: "Bitcoin", Rank: 1}, {Name: "Ethereum", Rank: 2}, {Name: "Solana", Rank: 3}}
+	ct := newSessionTestCointop(coins)
+	ct.State.favorites["Solana"] = true
+	ct.State.favorites["Ethereum"] = true
+	ct.State.page = 1
+	ct.State.selectedCoin = coins[2]
+	ct.State.filterByFavorites = true
+	s := ct.currentSession()
+
+	// the pages are counted from the favorites, which take two pages here and one page in the fixture
+	restored := newSessionTestCointop(coins)
+	restored.State.favorites = ct.State.favorites
+	restored.restoreSession(s)
+	if got := *restored.currentSession(); got != *s {
+		t.Errorf("restoreSession(%+v) restored %+v", *s, got)
+	}
+
+	restored = newSessionTestCointop(coins)
+	restored.restoreSession(s)
+	if restored.State.page != 0 {
+		t.Errorf("page with a single favorite = %d, want 0", restored.State.page)
+	}
+}
+
+func TestRestoreSessionRejectsInvalidValues(t *testing.T) {
+	coins := []*Coin{{Name: "Bitcoin", Rank: 1}, {Name: "Ethereum", Rank: 2}}
+	tests := []struct {
+		name string
+		s    session
+	}{
+		{"unknown sort column", session{SortBy: "notacolumn", SortDesc: true}},
+		{"chart height too small", session{ChartHeight: 4}},
+		{"chart height too large", session{ChartHeight: 31}},
+		{"unknown chart range", session{ChartRange: "2W"}},
+		{"missing watchlist", session{View: "favorites", Watchlist: "deleted"}},
+		{"page out of range", session{Page: 5}},
+		{"missing coin", session{Coin: "Delisted"}},
+	}
+
+	for _, test := range tests {
+		ct := newSessionTestCointop(coins)
+		ct.restoreSession(&test.s)
+		state := ct.State
+		if state.sortBy != "rank" || state.sortDesc {
+			t.Errorf("%s: sort = %s desc %v, want rank asc", test.name, state.sortBy, state.sortDesc)
+		}
+		if state.chartHeight != 10 {
+			t.Errorf("%s: chart height = %d, want 10", test.name, state.chartHeight)
+		}
+		if state.selectedChartRange != "1Y" {
+			t.Errorf("%s: chart range = %s, want 1Y", test.name, state.selectedChartRange)
+		}
+		if state.selectedWatchlist != defaultWatchlist {
+			t.Errorf("%s: watchlist = %s, want %s", test.name, state.selectedWatchlist, defaultWatchlist)
+		}
+		if state.page != 0 {
+			t.Errorf("%s: page = %d, want 0", test.name, state.page)
+		}
+		if state.selectedCoin != nil {
+			t.Errorf("%s: selected coin = %s, want none", test.name, state.selectedCoin.Name)
+		}
+	}
+}
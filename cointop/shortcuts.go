@@ -25,8 +25,19 @@ func DefaultShortcuts() map[string]string {
 		"ctrl+s":    "save",
 		"ctrl+S":    "save",
 		"ctrl+u":    "page_up",
+		"ctrl+x":    "clear_filter",
+		"ctrl+t":    "show_colorscheme_menu",
+		"=":         "show_calculator",
 		"ctrl+j":    "enlarge_chart",
 		"ctrl+k":    "shorten_chart",
+		"alt+j":     "enlarge_volume_chart",
+		"alt+k":     "shorten_volume_chart",
+		"alt+s":     "toggle_chart_sma",
+		"alt+e":     "toggle_chart_ema",
+		"alt+b":     "toggle_chart_bollinger",
+		"alt+r":     "toggle_chart_rsi",
+		"alt+m":     "toggle_chart_macd",
+		"alt+y":     "next_chart_scale",
 		"alt+up":    "sort_column_asc",
 		"alt+down":  "sort_column_desc",
 		"alt+left":  "sort_left_column",
@@ -49,14 +60,17 @@ func DefaultShortcuts() map[string]string {
 		"g":         "move_to_page_first_row",
 		"G":         "move_to_page_last_row",
 		"h":         "previous_page",
+		"i":         "toggle_chart_inspect",
 		"H":         "move_to_page_visible_first_row",
 		"j":         "move_down",
 		"k":         "move_up",
 		"l":         "next_page",
+		"K":         "toggle_chart_mode",
 		"L":         "move_to_page_visible_last_row",
 		"m":         "sort_column_market_cap",
 		"M":         "move_to_page_visible_middle_row",
 		"n":         "sort_column_name",
+		"N":         "previous_search_match",
 		"o":         "open_link",
 		"O":         "open_link",
 		"p":         "sort_column_price",
@@ -64,14 +78,22 @@ func DefaultShortcuts() map[string]string {
 		"r":         "sort_column_rank",
 		"s":         "sort_column_symbol",
 		"t":         "sort_column_total_supply",
+		"T":         "show_table_columns_menu",
+		"U":         "toggle_top_movers",
 		"u":         "sort_column_last_updated",
 		"v":         "sort_column_24h_volume",
+		"w":         "show_watchlists_menu",
+		"W":         "next_watchlist",
+		"x":         "toggle_compare_coin",
+		"X":         "toggle_compare_chart",
 		"q":         "quit_view",
 		"Q":         "quit_view",
-		"Y":         "sort_column_1Y_change",
+		"Y":         "sort_column_1y_change",
 		"$":         "last_page",
 		"?":         "help",
 		"/":         "open_search",
+		"|":         "open_filter",
+		":":         "open_command_palette",
 		"]":         "next_chart_range",
 		"[":         "previous_chart_range",
 		"}":         "last_chart_range",
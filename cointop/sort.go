@@ -54,7 +54,7 @@ func (ct *Cointop) sort(sortBy string, desc bool, list []*Coin, renderHeaders bo
 			return a.PercentChange1H < b.PercentChange1H
 		case "24hchange":
 			return a.PercentChange24H < b.PercentChange24H
-		case "7dchange":
+		case "7dchange", "sparkline":
 			return a.PercentChange7D < b.PercentChange7D
 		case "30dchange":
 			return a.PercentChange30D < b.PercentChange30D
@@ -64,8 +64,16 @@ func (ct *Cointop) sort(sortBy string, desc bool, list []*Coin, renderHeaders bo
 			return a.TotalSupply < b.TotalSupply
 		case "availablesupply":
 			return a.AvailableSupply < b.AvailableSupply
+		case "refreshchange":
+			return ct.refreshPriceChange(a) < ct.refreshPriceChange(b)
 		case "lastupdated":
 			return a.LastUpdated < b.LastUpdated
+		case ct.secondaryColumn("price"):
+			return a.SecondaryPrice < b.SecondaryPrice
+		case ct.secondaryColumn("marketcap"):
+			return a.SecondaryMarketCap < b.SecondaryMarketCap
+		case ct.secondaryColumn("balance"):
+			return a.SecondaryBalance < b.SecondaryBalance
 		default:
 			return a.Rank < b.Rank
 		}
@@ -92,14 +100,17 @@ func (ct *Cointop) sortDesc() error {
 
 func (ct *Cointop) sortPrevCol() error {
 	ct.debuglog("sortPrevCol()")
-	nextsortBy := ct.TableColumnOrder[0]
+	cols := ct.tableColumns(ct.currentTableView())
+	if len(cols) == 0 {
+		return nil
+	}
 	i := ct.getSortColIndex()
 	k := i - 1
 	if k < 0 {
 		k = 0
 	}
 
-	nextsortBy = ct.TableColumnOrder[k]
+	nextsortBy := cols[k]
 	ct.sort(nextsortBy, ct.State.sortDesc, ct.State.coins, true)
 	ct.UpdateTable()
 	return nil
@@ -107,15 +118,18 @@ func (ct *Cointop) sortPrevCol() error {
 
 func (ct *Cointop) sortNextCol() error {
 	ct.debuglog("sortNextCol()")
-	nextsortBy := ct.TableColumnOrder[0]
-	l := len(ct.TableColumnOrder)
+	cols := ct.tableColumns(ct.currentTableView())
+	l := len(cols)
+	if l == 0 {
+		return nil
+	}
 	i := ct.getSortColIndex()
 	k := i + 1
 	if k > l-1 {
 		k = l - 1
 	}
 
-	nextsortBy = ct.TableColumnOrder[k]
+	nextsortBy := cols[k]
 	ct.sort(nextsortBy, ct.State.sortDesc, ct.State.coins, true)
 	ct.UpdateTable()
 	return nil
@@ -141,7 +155,7 @@ func (ct *Cointop) sortfn(sortBy string, desc bool) func(g *gocui.Gui, v *gocui.
 
 func (ct *Cointop) getSortColIndex() int {
 	ct.debuglog("getSortColIndex()")
-	for i, col := range ct.TableColumnOrder {
+	for i, col := range ct.tableColumns(ct.currentTableView()) {
 		if ct.State.sortBy == col {
 			return i
 		}
@@ -0,0 +1,44 @@
+package cointop
+
+import (
+	"math"
+	"strings"
+)
+
+// sparklineBlocks are the block characters of the sparkline column from low to high
+var sparklineBlocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// sparkline returns the values resampled to the width as a line of block characters
+func sparkline(values []float64, width int) string {
+	if len(values) < 2 || width < 2 {
+		return ""
+	}
+
+	values = resampleSeries(values, width)
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		// NOTE: a flat line is drawn at the lowest block
+		if max > min {
+			i = int((v - min) / (max - min) * float64(len(sparklineBlocks)-1))
+		}
+		b.WriteRune(sparklineBlocks[i])
+	}
+
+	return b.String()
+}
+
+// sparklineChange returns the change from the first to the last value of the sparkline
+func sparklineChange(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	return values[len(values)-1] - values[0]
+}
@@ -2,6 +2,7 @@ package cointop
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cdyfng/coind/cointop/common/open"
 	"github.com/cdyfng/coind/cointop/common/pad"
@@ -29,32 +30,74 @@ func (statusbar *StatusbarView) Update(str string) error {
 	return nil
 }
 
+// statusbarHint is a shortcut hint of the statusbar and the action it runs when clicked
+type statusbarHint struct {
+	Text   string
+	Action string
+}
+
+// text returns the text of the hint, or only its key when the statusbar is compact. The help hint
+// keeps its label so the other shortcuts can be looked up
+func (hint statusbarHint) text(compact bool) string {
+	if !compact || hint.Action == "help" {
+		return hint.Text
+	}
+
+	return hint.Text[:strings.LastIndex(hint.Text, "]")+1]
+}
+
+// statusbarHints returns the shortcut hints shown at the start of the statusbar
+func (ct *Cointop) statusbarHints() []statusbarHint {
+	quit := statusbarHint{"[Q]Quit", "quit_view"}
+	if ct.State.portfolioVisible || ct.State.filterByFavorites {
+		quit.Text = "[Q]Return"
+	}
+	favorites := statusbarHint{"[F]Favorites", "toggle_show_favorites"}
+	if ct.State.filterByFavorites {
+		favorites = statusbarHint{"[Space]Unfavorite", "toggle_favorite"}
+		if ct.State.selectedWatchlist != defaultWatchlist {
+			favorites.Text = "[Space]Remove"
+		}
+	}
+	portfolio := statusbarHint{"[P]Portfolio", "toggle_portfolio"}
+	if ct.State.portfolioVisible {
+		portfolio = statusbarHint{"[E]Edit", "show_portfolio_edit_menu"}
+	}
+
+	return []statusbarHint{
+		quit,
+		{"[?]Help", "help"},
+		{"[Enter]Chart", "toggle_row_chart"},
+		{"[[ ]]Range", "next_chart_range"},
+		{"[/]Search", "open_search"},
+		{"[C]Convert", "show_currency_convert_menu"},
+		favorites,
+		portfolio,
+		{"[CTRL-S]Save", "save"},
+	}
+}
+
 // UpdateStatusbar updates the statusbar view
 func (ct *Cointop) UpdateStatusbar(s string) error {
 	ct.debuglog("UpdateStatusbar()")
 	currpage := ct.currentDisplayPage()
 	totalpages := ct.totalPagesDisplay()
-	var quitText string
-	var favoritesText string
-	var portfolioText string
-	if ct.State.portfolioVisible || ct.State.filterByFavorites {
-		quitText = "Return"
-	} else {
-		quitText = "Quit"
+
+	if ct.State.filter != nil {
+		s = fmt.Sprintf("[|]Filter: %s %s", ct.State.filterInput, s)
 	}
-	if ct.State.portfolioVisible {
-		portfolioText = "[E]Edit"
-	} else {
-		portfolioText = "[P]Portfolio"
-	}
-	if ct.State.filterByFavorites {
-		favoritesText = "[Space]Unfavorite"
-	} else {
-		favoritesText = "[F]Favorites"
+	if len(ct.State.keyConflicts) > 0 {
+		s = fmt.Sprintf("[?]Key conflicts: %d %s", len(ct.State.keyConflicts), s)
 	}
 
-	base := fmt.Sprintf("%s%s %sHelp %sChart %sRange %sSearch %sConvert %s %s %sSave", "[Q]", quitText, "[?]", "[Enter]", "[[ ]]", "[/]", "[C]", favoritesText, portfolioText, "[CTRL-S]")
-	str := pad.Right(fmt.Sprintf("%v %sPage %v/%v %s", base, "[← →]", currpage, totalpages, s), ct.maxTableWidth, " ")
+	compact := ct.State.screenLayout.CompactStatusbar
+	var hints []string
+	for _, hint := range ct.statusbarHints() {
+		hints = append(hints, hint.text(compact))
+	}
+	base := strings.Join(hints, " ")
+	page := statusbarHint{"[← →]Page ", "next_page"}.text(compact)
+	str := pad.Right(fmt.Sprintf("%v %s%v/%v %s", base, page, currpage, totalpages, s), ct.ClampedWidth(), " ")
 	v := fmt.Sprintf("v%s", ct.Version())
 	end := len(str) - len(v) + 2
 	if end > len(str) {
@@ -2,14 +2,9 @@ package cointop
 
 import (
 	"fmt"
-	"math"
 	"net/url"
-	"strconv"
 	"strings"
-	"time"
 
-	"github.com/cdyfng/coind/cointop/common/humanize"
-	"github.com/cdyfng/coind/cointop/common/pad"
 	"github.com/cdyfng/coind/cointop/common/table"
 )
 
@@ -23,28 +18,6 @@ func NewTableView() *TableView {
 	return &TableView{NewView("table")}
 }
 
-// TableColumnOrder returns the default order of the table columns
-func TableColumnOrder() []string {
-	return []string{
-		"rank",
-		"name",
-		"symbol",
-		"price",
-		"holdings",
-		"balance",
-		"marketcap",
-		"24hvolume",
-		"1hchange",
-		"7dchange",
-		"1dchange",
-		"1ychange",		
-		"totalsupply",
-		"availablesupply",
-		"percentholdings",
-		"lastupdated",
-	}
-}
-
 const dots = "..."
 
 // RefreshTable refreshes the table
@@ -54,161 +27,32 @@ func (ct *Cointop) RefreshTable() error {
 	ct.table = table.New().SetWidth(maxX)
 	ct.table.HideColumHeaders = true
 
+	var total float64
 	if ct.State.portfolioVisible {
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-
-		total := ct.getPortfolioTotal()
-
-		for _, coin := range ct.State.coins {
-			unix, _ := strconv.ParseInt(coin.LastUpdated, 10, 64)
-			lastUpdated := time.Unix(unix, 0).Format("15:04:05 Jan 02")
-			colorbalance := ct.colorscheme.TableColumnPrice
-			color24h := ct.colorscheme.TableColumnChange
-			if coin.PercentChange24H > 0 {
-				color24h = ct.colorscheme.TableColumnChangeUp
-			}
-			if coin.PercentChange24H < 0 {
-				color24h = ct.colorscheme.TableColumnChangeDown
-			}
-			name := coin.Name
-			star := ct.colorscheme.TableRow(" ")
-			if coin.Favorite {
-				star = ct.colorscheme.TableRowFavorite("*")
-			}
-
-			rank := fmt.Sprintf("%s%v", star, ct.colorscheme.TableRow(fmt.Sprintf("%6v ", coin.Rank)))
-			if len(name) > 20 {
-				name = fmt.Sprintf("%s%s", name[0:18], dots)
-			}
-
-			namecolor := ct.colorscheme.TableRow
-			if coin.Favorite {
-				namecolor = ct.colorscheme.TableRowFavorite
-			}
+		total = ct.getPortfolioTotal()
+	}
 
-			percentHoldings := (coin.Balance / total) * 1e2
-			if math.IsNaN(percentHoldings) {
-				percentHoldings = 0
-			}
+	cols := ct.visibleTableColumns(total)
+	for _, col := range cols {
+		ct.table.AddCol(col.Name).SetFixedWidth(col.Width).SetAlign(col.Align)
+	}
 
-			ct.table.AddRow(
-				rank,
-				namecolor(pad.Right(fmt.Sprintf("%.22s", name), 21, " ")),
-				ct.colorscheme.TableRow(pad.Right(fmt.Sprintf("%.6s", coin.Symbol), 5, " ")),
-				ct.colorscheme.TableRow(fmt.Sprintf("%13s", humanize.Commaf(coin.Price))),
-				ct.colorscheme.TableRow(fmt.Sprintf("%15s", strconv.FormatFloat(coin.Holdings, 'f', -1, 64))),
-				colorbalance(fmt.Sprintf("%15s", humanize.Commaf(coin.Balance))),
-				color24h(fmt.Sprintf("%8.2f%%", coin.PercentChange24H)),
-				ct.colorscheme.TableRow(fmt.Sprintf("%10.2f%%", percentHoldings)),
-				ct.colorscheme.TableRow(pad.Right(fmt.Sprintf("%17s", lastUpdated), 80, " ")),
-			)
+	for _, coin := range ct.State.coins {
+		if coin == nil {
+			continue
 		}
-	} else {
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")
-		ct.table.AddCol("")		
-		for _, coin := range ct.State.coins {
-			if coin == nil {
-				continue
-			}
-			unix, _ := strconv.ParseInt(coin.LastUpdated, 10, 64)
-			lastUpdated := time.Unix(unix, 0).Format("15:04:05 Jan 02")
-			namecolor := ct.colorscheme.TableRow
-			color1h := ct.colorscheme.TableColumnChange
-			color24h := ct.colorscheme.TableColumnChange
-			color7d := ct.colorscheme.TableColumnChange
-			color30d := ct.colorscheme.TableColumnChange
-			color1y := ct.colorscheme.TableColumnChange			
-			if coin.Favorite {
-				namecolor = ct.colorscheme.TableRowFavorite
-			}
-			if coin.PercentChange1H > 0 {
-				color1h = ct.colorscheme.TableColumnChangeUp
-			}
-			if coin.PercentChange1H < 0 {
-				color1h = ct.colorscheme.TableColumnChangeDown
-			}
-			if coin.PercentChange24H > 0 {
-				color24h = ct.colorscheme.TableColumnChangeUp
-			}
-			if coin.PercentChange24H < 0 {
-				color24h = ct.colorscheme.TableColumnChangeDown
-			}
-			if coin.PercentChange7D > 0 {
-				color7d = ct.colorscheme.TableColumnChangeUp
-			}
-			if coin.PercentChange7D < 0 {
-				color7d = ct.colorscheme.TableColumnChangeDown
-			}
-			if coin.PercentChange30D > 0 {
-				color30d = ct.colorscheme.TableColumnChangeUp
-			}
-			if coin.PercentChange30D < 0 {
-				color30d = ct.colorscheme.TableColumnChangeDown
-			}		
-			if coin.PercentChange1Y > 0 {
-				color1y = ct.colorscheme.TableColumnChangeUp
-			}
-			if coin.PercentChange1Y < 0 {
-				color1y = ct.colorscheme.TableColumnChangeDown
-			}							
-			name := coin.Name
-			star := ct.colorscheme.TableRow(" ")
-			if coin.Favorite {
-				star = ct.colorscheme.TableRowFavorite("*")
-			}
-			rank := fmt.Sprintf("%s%v", star, ct.colorscheme.TableRow(fmt.Sprintf("%6v ", coin.Rank)))
-			if len(name) > 20 {
-				name = fmt.Sprintf("%s%s", name[0:18], dots)
-			}
-
-			symbolpadding := 5
-			// NOTE: this is to adjust padding by 1 because when all name rows are
-			// yellow it messes the spacing (need to debug)
-			if ct.State.filterByFavorites {
-				symbolpadding = 6
-			}
-			ct.table.AddRow(
-				rank,
-				namecolor(pad.Right(fmt.Sprintf("%.22s", name), 21, " ")),
-				ct.colorscheme.TableRow(pad.Right(fmt.Sprintf("%.6s", coin.Symbol), symbolpadding, " ")),
-				ct.colorscheme.TableColumnPrice(fmt.Sprintf("%12s", humanize.Commaf(coin.Price))),
-				ct.colorscheme.TableRow(fmt.Sprintf("%18s", humanize.Commaf(coin.MarketCap))),
-				ct.colorscheme.TableRow(fmt.Sprintf("%15s", humanize.Commaf(coin.Volume24H))),
-				color1h(fmt.Sprintf("%8.2f%%", coin.PercentChange1H)),
-				color24h(fmt.Sprintf("%8.2f%%", coin.PercentChange24H)),
-				color7d(fmt.Sprintf("%8.2f%%", coin.PercentChange7D)),
-				color30d(fmt.Sprintf("%8.2f%%", coin.PercentChange30D)),
-				color1y(fmt.Sprintf("%8.2f%%", coin.PercentChange1Y)),
-				ct.colorscheme.TableRow(fmt.Sprintf("%21s", humanize.Commaf(coin.TotalSupply))),
-				ct.colorscheme.TableRow(fmt.Sprintf("%18s", humanize.Commaf(coin.AvailableSupply))),
-				ct.colorscheme.TableRow(fmt.Sprintf("%18s", lastUpdated)),
-				// TODO: add %percent of cap
-			)
+		var row []interface{}
+		for _, col := range cols {
+			row = append(row, col.format(coin, col.Width))
 		}
+		ct.table.AddRow(row...)
 	}
 
 	// highlight last row if current row is out of bounds (can happen when switching views)
 	currentrow := ct.HighlightedRowIndex()
+	if ct.State.restoredCoin != "" && len(ct.State.coins) > 0 {
+		currentrow = ct.restoredCoinRow(currentrow)
+	}
 	if len(ct.State.coins) > currentrow {
 		ct.highlightRow(currentrow)
 	}
@@ -268,23 +112,18 @@ func (ct *Cointop) GetTableCoinsSlice() []*Coin {
 	sliced := []*Coin{}
 	start := ct.State.page * ct.State.perPage
 	end := start + ct.State.perPage
-	allCoins := ct.AllCoins()
+	allCoins := ct.filterCoins(ct.AllCoins())
 	size := len(allCoins)
 	if start < 0 {
 		start = 0
 	}
-	if end >= size-1 {
-		start = int(math.Floor(float64(start/100)) * 100)
-		end = size - 1
-	}
-	if start < 0 {
-		start = 0
-	}
-	if end >= size {
-		end = size - 1
+	if start >= size && size > 0 {
+		// NOTE: go to the last page if the page is out of range, e.g. after filtering
+		start = ((size - 1) / ct.State.perPage) * ct.State.perPage
+		end = start + ct.State.perPage
 	}
-	if end < 0 {
-		end = 0
+	if end > size {
+		end = size
 	}
 	if start >= end {
 		return nil
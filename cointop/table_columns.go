@@ -0,0 +1,506 @@
+package cointop
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cdyfng/coind/cointop/common/table"
+	"golang.org/x/text/language"
+)
+
+// TableColumn is the definition of a table column
+type TableColumn struct {
+	Name  string
+	Label string
+	Width int
+	Align table.Align
+	// PortfolioOnly is true if the column is only available in the portfolio view
+	PortfolioOnly bool
+	format        func(coin *Coin, width int) string
+}
+
+// table views which have their own column settings
+var tableViews = []string{"coins", "favorites", "portfolio"}
+
+const (
+	minTableColumnWidth = 3
+	maxTableColumnWidth = 60
+	// compactTableColumnWidth is the width of the number columns in the compact format
+	compactTableColumnWidth = 10
+	defaultCompactPrecision = 2
+	maxCompactPrecision     = 4
+)
+
+// tableColumnFormats are the number formats of the columns. The auto format shows the full number
+// unless it doesn't fit in the column, e.g. when the columns are shrunk to fit in the terminal
+var tableColumnFormats = []string{"auto", "full", "compact"}
+
+// compactTableColumns are the columns of large numbers which can be shown in the compact format, e.g. 1.23T
+var compactTableColumns = map[string]bool{
+	"marketcap":       true,
+	"24hvolume":       true,
+	"totalsupply":     true,
+	"availablesupply": true,
+}
+
+// TableColumnOrder returns the default order of the table columns
+func TableColumnOrder() []string {
+	return []string{
+		"rank",
+		"name",
+		"symbol",
+		"price",
+		"refreshchange",
+		"holdings",
+		"balance",
+		"marketcap",
+		"24hvolume",
+		"1hchange",
+		"24hchange",
+		"7dchange",
+		"sparkline",
+		"30dchange",
+		"1ychange",
+		"totalsupply",
+		"availablesupply",
+		"percentholdings",
+		"lastupdated",
+	}
+}
+
+// TableColumnPriority returns the default priority of the table columns, highest first. When the
+// columns don't fit in the terminal the lowest priority columns are dropped first
+func TableColumnPriority() []string {
+	return []string{
+		"rank",
+		"name",
+		"price",
+		"24hchange",
+		"symbol",
+		"holdings",
+		"balance",
+		"percentholdings",
+		"marketcap",
+		"1hchange",
+		"7dchange",
+		"refreshchange",
+		"24hvolume",
+		"sparkline",
+		"30dchange",
+		"1ychange",
+		"lastupdated",
+		"availablesupply",
+		"totalsupply",
+	}
+}
+
+// optionalTableColumns are the columns which are hidden unless they're enabled in the column settings
+var optionalTableColumns = map[string]bool{
+	"refreshchange": true,
+}
+
+// defaultPortfolioTableColumns returns the default columns of the portfolio view
+func defaultPortfolioTableColumns() []string {
+	return []string{
+		"rank",
+		"name",
+		"symbol",
+		"price",
+		"holdings",
+		"balance",
+		"24hchange",
+		"percentholdings",
+		"lastupdated",
+	}
+}
+
+// currentTableView returns the name of the table view that is being shown
+func (ct *Cointop) currentTableView() string {
+	if ct.State.portfolioVisible {
+		return "portfolio"
+	}
+	if ct.State.filterByFavorites {
+		return "favorites"
+	}
+
+	return "coins"
+}
+
+// tableColumnDefs returns the definitions of all the table columns keyed by column name
+func (ct *Cointop) tableColumnDefs(portfolioTotal float64) map[string]*TableColumn {
+	row := ct.colorscheme.TableRow
+	price := ct.colorscheme.TableColumnPrice
+	colorbalance := ct.colorscheme.TableColumnPrice
+	change := func(name, label string, fn func(coin *Coin) float64) *TableColumn {
+		return &TableColumn{name, label, 9, table.AlignRight, false, func(coin *Coin, width int) string {
+			return ct.changeColor(fn(coin))(ct.formatPercent(fn(coin)))
+		}}
+	}
+	number := func(name, label string, width int, colorfn func(a ...interface{}) string, fn func(coin *Coin) float64) *TableColumn {
+		return &TableColumn{name, label, width, table.AlignRight, false, func(coin *Coin, width int) string {
+			return colorfn(ct.formatTableNumber(name, fn(coin), width))
+		}}
+	}
+
+	cols := []*TableColumn{
+		{"rank", "[r]ank", 7, table.AlignLeft, false, func(coin *Coin, width int) string {
+			star := row(" ")
+			if coin.Favorite {
+				star = ct.colorscheme.TableRowFavorite("*")
+			}
+			if i := ct.compareCoinIndex(coin); i >= 0 {
+				star = ct.colorscheme.ChartSeriesSprintf(i)("+")
+			}
+			return fmt.Sprintf("%s%v", star, row(fmt.Sprintf("%*v", width-1, coin.Rank)))
+		}},
+		{"name", "[n]ame", 21, table.AlignLeft, false, func(coin *Coin, width int) string {
+			name := coin.Name
+			if len(name) > width && width > len(dots) {
+				name = fmt.Sprintf("%s%s", name[0:width-len(dots)], dots)
+			}
+			if coin.Favorite {
+				return ct.colorscheme.TableRowFavorite(name)
+			}
+			return row(name)
+		}},
+		{"symbol", "[s]ymbol", 9, table.AlignLeft, false, func(coin *Coin, width int) string {
+			return row(coin.Symbol)
+		}},
+		{"price", "[p]rice", 12, table.AlignRight, false, func(coin *Coin, width int) string {
+			return ct.priceFlashColor(coin, price)(ct.formatNumber(coin.Price))
+		}},
+		{"refreshchange", "Δ refresh", 12, table.AlignRight, false, func(coin *Coin, width int) string {
+			change := ct.refreshPriceChange(coin)
+			return ct.changeColor(change)(ct.formatPriceChange(change))
+		}},
+		number("marketcap", "[m]arket cap", 18, row, func(coin *Coin) float64 { return coin.MarketCap }),
+		number("24hvolume", "24H [v]olume", 15, row, func(coin *Coin) float64 { return coin.Volume24H }),
+		change("1hchange", "[1]H%", func(coin *Coin) float64 { return coin.PercentChange1H }),
+		change("24hchange", "[2]4H%", func(coin *Coin) float64 { return coin.PercentChange24H }),
+		change("7dchange", "[7]D%", func(coin *Coin) float64 { return coin.PercentChange7D }),
+		{"sparkline", "7D trend", 14, table.AlignLeft, false, func(coin *Coin, width int) string {
+			return ct.changeColor(sparklineChange(coin.Sparkline7D))(sparkline(coin.Sparkline7D, width))
+		}},
+		change("30dchange", "[3]0D%", func(coin *Coin) float64 { return coin.PercentChange30D }),
+		change("1ychange", "1[Y]%", func(coin *Coin) float64 { return coin.PercentChange1Y }),
+		number("totalsupply", "[t]otal supply", 21, row, func(coin *Coin) float64 { return coin.TotalSupply }),
+		number("availablesupply", "[a]vailable supply", 19, row, func(coin *Coin) float64 { return coin.AvailableSupply }),
+		{"lastupdated", "last [u]pdated", 18, table.AlignRight, false, func(coin *Coin, width int) string {
+			unix, _ := strconv.ParseInt(coin.LastUpdated, 10, 64)
+			return row(time.Unix(unix, 0).Format("15:04:05 Jan 02"))
+		}},
+		{"holdings", "[h]oldings", 15, table.AlignRight, true, func(coin *Coin, width int) string {
+			if ct.State.locale != language.Und {
+				return row(ct.formatNumber(coin.Holdings))
+			}
+			return row(strconv.FormatFloat(coin.Holdings, 'f', -1, 64))
+		}},
+		{"balance", "[b]alance", 15, table.AlignRight, true, func(coin *Coin, width int) string {
+			return colorbalance(ct.formatNumber(coin.Balance))
+		}},
+		{"percentholdings", "%holdings", 11, table.AlignRight, true, func(coin *Coin, width int) string {
+			percentHoldings := (coin.Balance / portfolioTotal) * 1e2
+			if math.IsNaN(percentHoldings) {
+				percentHoldings = 0
+			}
+			return row(ct.formatPercent(percentHoldings))
+		}},
+	}
+
+	if cur := ct.State.secondaryCurrencyConversion; cur != "" {
+		// NOTE: the secondary currency values are unavailable until there's an exchange rate
+		secondary := func(col *TableColumn) *TableColumn {
+			format := col.format
+			col.format = func(coin *Coin, width int) string {
+				if ct.State.secondaryConversionRate == 0 {
+					return row("-")
+				}
+				return format(coin, width)
+			}
+			return col
+		}
+		cols = append(cols,
+			secondary(&TableColumn{ct.secondaryColumn("price"), "price_" + cur, 14, table.AlignRight, false, func(coin *Coin, width int) string {
+				return ct.priceFlashColor(coin, price)(ct.formatNumber(coin.SecondaryPrice))
+			}}),
+			secondary(number(ct.secondaryColumn("marketcap"), "marketcap_"+cur, 18, row, func(coin *Coin) float64 { return coin.SecondaryMarketCap })),
+			secondary(&TableColumn{ct.secondaryColumn("balance"), "balance_" + cur, 15, table.AlignRight, true, func(coin *Coin, width int) string {
+				return colorbalance(ct.formatNumber(math.Round(coin.SecondaryBalance*1e2) / 1e2))
+			}}),
+		)
+	}
+
+	defs := make(map[string]*TableColumn, len(cols))
+	for _, col := range cols {
+		defs[col.Name] = col
+	}
+
+	return defs
+}
+
+// changeColor returns the color function for a percent change value
+func (ct *Cointop) changeColor(change float64) func(a ...interface{}) string {
+	if change > 0 {
+		return ct.colorscheme.TableColumnChangeUp
+	}
+	if change < 0 {
+		return ct.colorscheme.TableColumnChangeDown
+	}
+
+	return ct.colorscheme.TableColumnChange
+}
+
+// withSecondaryColumns inserts the secondary currency columns after their primary counterparts
+func (ct *Cointop) withSecondaryColumns(cols []string) []string {
+	var ret []string
+	for _, col := range cols {
+		ret = append(ret, col)
+		switch col {
+		case "price", "marketcap", "balance":
+			if secondary := ct.secondaryColumn(col); secondary != "" {
+				ret = append(ret, secondary)
+			}
+		}
+	}
+
+	return ret
+}
+
+// availableTableColumns returns all the columns that can be shown in the table view
+func (ct *Cointop) availableTableColumns(view string) []string {
+	defs := ct.tableColumnDefs(0)
+	var cols []string
+	for _, col := range ct.withSecondaryColumns(TableColumnOrder()) {
+		def, ok := defs[col]
+		if !ok {
+			continue
+		}
+		if def.PortfolioOnly && view != "portfolio" {
+			continue
+		}
+		cols = append(cols, col)
+	}
+
+	return cols
+}
+
+// defaultTableColumns returns the columns shown by default in the table view
+func (ct *Cointop) defaultTableColumns(view string) []string {
+	if view == "portfolio" {
+		return ct.withSecondaryColumns(defaultPortfolioTableColumns())
+	}
+
+	var cols []string
+	for _, col := range ct.availableTableColumns(view) {
+		if !optionalTableColumns[col] {
+			cols = append(cols, col)
+		}
+	}
+
+	return cols
+}
+
+// tableColumns returns the ordered list of visible columns for the table view
+func (ct *Cointop) tableColumns(view string) []string {
+	cols, ok := ct.State.tableColumns[view]
+	if !ok {
+		return ct.defaultTableColumns(view)
+	}
+
+	available := make(map[string]bool)
+	for _, col := range ct.availableTableColumns(view) {
+		available[col] = true
+	}
+
+	var ret []string
+	for _, col := range cols {
+		if available[col] {
+			ret = append(ret, col)
+		}
+	}
+
+	// NOTE: the configured columns may all be unavailable, e.g. only secondary currency columns
+	if len(ret) == 0 {
+		return ct.defaultTableColumns(view)
+	}
+
+	return ret
+}
+
+// isTableColumn returns true if the name is a table column or a secondary currency column, e.g. "price_eur"
+func isTableColumn(name string) bool {
+	for _, col := range TableColumnOrder() {
+		if col == name {
+			return true
+		}
+	}
+	for _, col := range []string{"price", "marketcap", "balance"} {
+		if strings.HasPrefix(name, col+"_") && len(name) > len(col)+1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tableColumnWidth returns the configured width of the column in the table view
+func (ct *Cointop) tableColumnWidth(view string, col *TableColumn) int {
+	if widths, ok := ct.State.tableColumnWidths[view]; ok {
+		if w, ok := widths[col.Name]; ok && w > 0 {
+			return w
+		}
+	}
+	if isCompactTableColumn(col.Name) && ct.tableColumnFormat(view, col.Name) == "compact" {
+		return compactTableColumnWidth
+	}
+
+	return col.Width
+}
+
+func isTableColumnFormat(format string) bool {
+	for _, f := range tableColumnFormats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCompactTableColumn returns true if the column can be shown in the compact format
+func isCompactTableColumn(name string) bool {
+	// NOTE: the secondary currency columns are named after their primary column, e.g. marketcap_eur
+	return compactTableColumns[strings.SplitN(name, "_", 2)[0]]
+}
+
+// tableColumnFormat returns the number format of the column in the table view
+func (ct *Cointop) tableColumnFormat(view string, name string) string {
+	if format, ok := ct.State.tableColumnFormats[view][name]; ok {
+		return format
+	}
+
+	return "auto"
+}
+
+// formatTableNumber returns the number of the column in its format. In the auto format the number is
+// compacted if it doesn't fit in the width
+func (ct *Cointop) formatTableNumber(name string, v float64, width int) string {
+	full := ct.formatNumber(v)
+	switch ct.tableColumnFormat(ct.currentTableView(), name) {
+	case "full":
+		return full
+	case "compact":
+		return ct.formatCompact(v)
+	}
+	if utf8.RuneCountInString(full) > width {
+		return ct.formatCompact(v)
+	}
+
+	return full
+}
+
+// visibleTableColumns returns the definitions of the visible columns of the current table view with their widths
+func (ct *Cointop) visibleTableColumns(portfolioTotal float64) []*TableColumn {
+	view := ct.currentTableView()
+	defs := ct.tableColumnDefs(portfolioTotal)
+	var cols []*TableColumn
+	for _, name := range ct.tableColumns(view) {
+		def, ok := defs[name]
+		if !ok {
+			continue
+		}
+		col := *def
+		col.Width = ct.tableColumnWidth(view, def)
+		cols = append(cols, &col)
+	}
+
+	width := ct.ClampedWidth()
+	if tableColumnsWidth(cols) > width {
+		// NOTE: compact the large numbers before dropping columns
+		for _, col := range cols {
+			if isCompactTableColumn(col.Name) && ct.tableColumnFormat(view, col.Name) == "auto" && col.Width > compactTableColumnWidth {
+				col.Width = compactTableColumnWidth
+			}
+		}
+	}
+
+	return fitTableColumns(cols, width, ct.tableColumnPriority())
+}
+
+// tableColumnPriority returns the configured or the default column priority, with the secondary
+// currency columns right after their primary columns
+func (ct *Cointop) tableColumnPriority() []string {
+	if len(ct.State.tableColumnPriority) > 0 {
+		return ct.withSecondaryColumns(ct.State.tableColumnPriority)
+	}
+
+	return ct.withSecondaryColumns(TableColumnPriority())
+}
+
+// tableColumnsWidth returns the width the columns take in the table
+func tableColumnsWidth(cols []*TableColumn) int {
+	// NOTE: each column is followed by a space
+	width := 0
+	for _, col := range cols {
+		width += col.Width + 1
+	}
+
+	return width
+}
+
+// fitTableColumns drops the columns with the lowest priority until the columns fit in the width.
+// Columns missing from the priority list have the lowest priority, the rightmost is dropped first
+func fitTableColumns(cols []*TableColumn, width int, priority []string) []*TableColumn {
+	ranks := make(map[string]int, len(priority))
+	for i, name := range priority {
+		if _, ok := ranks[name]; !ok {
+			ranks[name] = i
+		}
+	}
+	rank := func(col *TableColumn) int {
+		if r, ok := ranks[col.Name]; ok {
+			return r
+		}
+		return len(priority)
+	}
+
+	total := tableColumnsWidth(cols)
+	fitted := append([]*TableColumn{}, cols...)
+	for total > width && len(fitted) > 1 {
+		drop := 0
+		for i, col := range fitted {
+			if rank(col) >= rank(fitted[drop]) {
+				drop = i
+			}
+		}
+		total -= fitted[drop].Width + 1
+		fitted = append(fitted[:drop], fitted[drop+1:]...)
+	}
+
+	return fitted
+}
+
+// tableColumnHeaderLabel returns the header text of the column
+func (ct *Cointop) tableColumnHeaderLabel(col *TableColumn) string {
+	switch col.Name {
+	case "price", "balance":
+		return ct.formatCurrency(ct.currencySymbol(), col.Label)
+	}
+
+	return col.Label
+}
+
+func clampTableColumnWidth(w int) int {
+	if w < minTableColumnWidth {
+		return minTableColumnWidth
+	}
+	if w > maxTableColumnWidth {
+		return maxTableColumnWidth
+	}
+
+	return w
+}
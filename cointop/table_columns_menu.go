@@ -0,0 +1,332 @@
+package cointop
+
+import (
+	"fmt"
+	"strings"
+
+	color "github.com/cdyfng/coind/cointop/common/color"
+	"github.com/cdyfng/coind/cointop/common/pad"
+)
+
+// TableColumnsMenuView is structure for table columns menu view
+type TableColumnsMenuView struct {
+	*View
+}
+
+// NewTableColumnsMenuView returns a new table columns menu view
+func NewTableColumnsMenuView() *TableColumnsMenuView {
+	return &TableColumnsMenuView{NewView("columnsmenu")}
+}
+
+// tableColumnsMenuState is the pending state of the table columns menu
+type tableColumnsMenuState struct {
+	view    string
+	index   int
+	columns map[string][]string
+	widths  map[string]map[string]int
+}
+
+// columnsMenuItems returns the menu items of the view; visible columns first, in order, followed by the hidden columns
+func (ct *Cointop) columnsMenuItems() []string {
+	menu := ct.State.columnsMenu
+	items := append([]string{}, menu.columns[menu.view]...)
+	visible := make(map[string]bool)
+	for _, col := range items {
+		visible[col] = true
+	}
+	for _, col := range ct.availableTableColumns(menu.view) {
+		if !visible[col] {
+			items = append(items, col)
+		}
+	}
+
+	return items
+}
+
+// columnsMenuWidth returns the pending width of the column
+func (ct *Cointop) columnsMenuWidth(view string, col *TableColumn) int {
+	if w, ok := ct.State.columnsMenu.widths[view][col.Name]; ok {
+		return w
+	}
+
+	return col.Width
+}
+
+func (ct *Cointop) updateTableColumnsMenu() {
+	ct.debuglog("updateTableColumnsMenu()")
+	menu := ct.State.columnsMenu
+	title := "Table Columns"
+	header := ct.colorscheme.MenuHeader(fmt.Sprintf(" %s %s\n\n", title, pad.Left("[q] close menu ", ct.ClampedWidth()-len(title)-1, " ")))
+
+	var tabs []string
+	for _, view := range tableViews {
+		if view == menu.view {
+			tabs = append(tabs, ct.colorscheme.MenuLabelActive(color.Bold(fmt.Sprintf("[%s]", view))))
+		} else {
+			tabs = append(tabs, ct.colorscheme.MenuLabel(fmt.Sprintf(" %s ", view)))
+		}
+	}
+	helpline := fmt.Sprintf(" %s\n [space] toggle [J/K] move [+/-] width [r] reset [tab] view [enter] apply\n\n", strings.Join(tabs, " "))
+
+	defs := ct.tableColumnDefs(0)
+	visible := make(map[string]bool)
+	for _, col := range menu.columns[menu.view] {
+		visible[col] = true
+	}
+
+	var body string
+	for i, name := range ct.columnsMenuItems() {
+		def, ok := defs[name]
+		if !ok {
+			continue
+		}
+		check := " "
+		if visible[name] {
+			check = "x"
+		}
+		label := ct.colorscheme.MenuLabel(fmt.Sprintf("%-20s", name))
+		cursor := " "
+		if i == menu.index {
+			cursor = ct.colorscheme.MenuLabelActive(color.Bold(">"))
+			label = ct.colorscheme.MenuLabelActive(color.Bold(fmt.Sprintf("%-20s", name)))
+		}
+		width := ct.colorscheme.Menu(fmt.Sprintf("%3d", ct.columnsMenuWidth(menu.view, def)))
+		body = fmt.Sprintf("%s %s [%s] %s %s\n", body, cursor, check, label, width)
+	}
+
+	content := fmt.Sprintf("%s%s%s", header, helpline, body)
+	ct.Update(func() error {
+		if ct.Views.TableColumnsMenu.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.TableColumnsMenu.Backing().Clear()
+		ct.Views.TableColumnsMenu.Backing().Frame = true
+		fmt.Fprintln(ct.Views.TableColumnsMenu.Backing(), content)
+		return nil
+	})
+}
+
+func (ct *Cointop) showTableColumnsMenu() error {
+	ct.debuglog("showTableColumnsMenu()")
+	menu := &tableColumnsMenuState{
+		view:    ct.currentTableView(),
+		columns: make(map[string][]string),
+		widths:  make(map[string]map[string]int),
+	}
+	for _, view := range tableViews {
+		menu.columns[view] = ct.tableColumns(view)
+		menu.widths[view] = make(map[string]int)
+		for col, w := range ct.State.tableColumnWidths[view] {
+			menu.widths[view][col] = w
+		}
+	}
+
+	ct.State.columnsMenu = menu
+	ct.State.columnsMenuVisible = true
+	ct.updateTableColumnsMenu()
+	ct.SetActiveView(ct.Views.TableColumnsMenu.Name())
+	return nil
+}
+
+func (ct *Cointop) hideTableColumnsMenu() error {
+	ct.debuglog("hideTableColumnsMenu()")
+	ct.State.columnsMenuVisible = false
+	ct.SetViewOnBottom(ct.Views.TableColumnsMenu.Name())
+	ct.SetActiveView(ct.Views.Table.Name())
+	ct.Update(func() error {
+		if ct.Views.TableColumnsMenu.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.TableColumnsMenu.Backing().Clear()
+		ct.Views.TableColumnsMenu.Backing().Frame = false
+		fmt.Fprintln(ct.Views.TableColumnsMenu.Backing(), "")
+		return nil
+	})
+	return nil
+}
+
+func (ct *Cointop) toggleTableColumnsMenu() error {
+	ct.debuglog("toggleTableColumnsMenu()")
+	if ct.State.columnsMenuVisible {
+		return ct.hideTableColumnsMenu()
+	}
+	return ct.showTableColumnsMenu()
+}
+
+// columnsMenuCursorFn returns a function which moves the menu selection by the given offset
+func (ct *Cointop) columnsMenuCursorFn(offset int) func() error {
+	return func() error {
+		ct.debuglog("columnsMenuCursor()")
+		menu := ct.State.columnsMenu
+		if menu == nil {
+			return nil
+		}
+		n := len(ct.columnsMenuItems())
+		menu.index += offset
+		if menu.index >= n {
+			menu.index = n - 1
+		}
+		if menu.index < 0 {
+			menu.index = 0
+		}
+		ct.updateTableColumnsMenu()
+		return nil
+	}
+}
+
+// columnsMenuToggle toggles the visibility of the selected column
+func (ct *Cointop) columnsMenuToggle() error {
+	ct.debuglog("columnsMenuToggle()")
+	menu := ct.State.columnsMenu
+	if menu == nil {
+		return nil
+	}
+	items := ct.columnsMenuItems()
+	if menu.index >= len(items) {
+		return nil
+	}
+	name := items[menu.index]
+	cols := menu.columns[menu.view]
+	for i, col := range cols {
+		if col == name {
+			// NOTE: keep at least one column visible
+			if len(cols) == 1 {
+				return nil
+			}
+			menu.columns[menu.view] = append(cols[:i:i], cols[i+1:]...)
+			// keep the selection on the column which moved to the hidden section
+			menu.index = len(menu.columns[menu.view])
+			ct.updateTableColumnsMenu()
+			return nil
+		}
+	}
+
+	menu.columns[menu.view] = append(cols, name)
+	menu.index = len(menu.columns[menu.view]) - 1
+	ct.updateTableColumnsMenu()
+	return nil
+}
+
+// columnsMenuMoveFn returns a function which moves the selected visible column by the given offset
+func (ct *Cointop) columnsMenuMoveFn(offset int) func() error {
+	return func() error {
+		ct.debuglog("columnsMenuMove()")
+		menu := ct.State.columnsMenu
+		if menu == nil {
+			return nil
+		}
+		cols := menu.columns[menu.view]
+		i := menu.index
+		k := i + offset
+		if i >= len(cols) || k < 0 || k >= len(cols) {
+			return nil
+		}
+		cols[i], cols[k] = cols[k], cols[i]
+		menu.index = k
+		ct.updateTableColumnsMenu()
+		return nil
+	}
+}
+
+// columnsMenuWidthFn returns a function which changes the width of the selected column by the given offset
+func (ct *Cointop) columnsMenuWidthFn(offset int) func() error {
+	return func() error {
+		ct.debuglog("columnsMenuWidth()")
+		menu := ct.State.columnsMenu
+		if menu == nil {
+			return nil
+		}
+		items := ct.columnsMenuItems()
+		if menu.index >= len(items) {
+			return nil
+		}
+		def, ok := ct.tableColumnDefs(0)[items[menu.index]]
+		if !ok {
+			return nil
+		}
+		w := clampTableColumnWidth(ct.columnsMenuWidth(menu.view, def) + offset)
+		if w == def.Width {
+			delete(menu.widths[menu.view], def.Name)
+		} else {
+			menu.widths[menu.view][def.Name] = w
+		}
+		ct.updateTableColumnsMenu()
+		return nil
+	}
+}
+
+// columnsMenuNextView switches the menu to the next table view
+func (ct *Cointop) columnsMenuNextView() error {
+	ct.debuglog("columnsMenuNextView()")
+	menu := ct.State.columnsMenu
+	if menu == nil {
+		return nil
+	}
+	for i, view := range tableViews {
+		if view == menu.view {
+			menu.view = tableViews[(i+1)%len(tableViews)]
+			break
+		}
+	}
+	menu.index = 0
+	ct.updateTableColumnsMenu()
+	return nil
+}
+
+// columnsMenuReset resets the columns of the view to the defaults
+func (ct *Cointop) columnsMenuReset() error {
+	ct.debuglog("columnsMenuReset()")
+	menu := ct.State.columnsMenu
+	if menu == nil {
+		return nil
+	}
+	menu.columns[menu.view] = ct.defaultTableColumns(menu.view)
+	menu.widths[menu.view] = make(map[string]int)
+	menu.index = 0
+	ct.updateTableColumnsMenu()
+	return nil
+}
+
+// columnsMenuApply applies and saves the pending column settings
+func (ct *Cointop) columnsMenuApply() error {
+	ct.debuglog("columnsMenuApply()")
+	menu := ct.State.columnsMenu
+	if menu == nil {
+		return ct.hideTableColumnsMenu()
+	}
+	for _, view := range tableViews {
+		if isDefaultTableColumns(menu.columns[view], ct.defaultTableColumns(view)) {
+			delete(ct.State.tableColumns, view)
+		} else {
+			ct.State.tableColumns[view] = menu.columns[view]
+		}
+		if len(menu.widths[view]) == 0 {
+			delete(ct.State.tableColumnWidths, view)
+		} else {
+			ct.State.tableColumnWidths[view] = menu.widths[view]
+		}
+	}
+
+	ct.hideTableColumnsMenu()
+	if err := ct.Save(); err != nil {
+		return err
+	}
+
+	go ct.UpdateTable()
+	return nil
+}
+
+func isDefaultTableColumns(cols, defaults []string) bool {
+	if len(cols) != len(defaults) {
+		return false
+	}
+	for i := range cols {
+		if cols[i] != defaults[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,383 @@
+package cointop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	types "github.com/cdyfng/coind/cointop/common/api/types"
+	"github.com/cdyfng/coind/cointop/common/filecache"
+	"github.com/cdyfng/coind/cointop/common/pad"
+)
+
+// topMoversPeriods are the periods of the percent changes the top movers are ranked by
+var topMoversPeriods = []string{"1h", "24h", "7d"}
+
+// topMoversMaxRows is the maximum number of coins of each list of the top movers
+const topMoversMaxRows = 25
+
+// rankHistoryDateFormat is the format of the days of the rank history
+const rankHistoryDateFormat = "2006-01-02"
+
+var rankHistoryMux sync.Mutex
+
+// TopMoversView is structure for top movers view
+type TopMoversView struct {
+	*View
+}
+
+// NewTopMoversView returns a new top movers view
+func NewTopMoversView() *TopMoversView {
+	return &TopMoversView{NewView("topmovers")}
+}
+
+// rankChange is the change of the market cap rank of a coin
+type rankChange struct {
+	Coin *Coin
+	From int
+	To   int
+}
+
+// Delta returns the number of places the coin moved up, or down if negative
+func (change rankChange) Delta() int {
+	return change.From - change.To
+}
+
+// trackRankChange keeps the rank of the coin before the update if it changed
+func trackRankChange(coin *Coin, update *Coin) {
+	if coin.Rank == 0 || update.Rank == 0 || coin.Rank == update.Rank {
+		return
+	}
+
+	coin.PreviousRank = coin.Rank
+	coin.RankChangedAt = time.Now()
+}
+
+// coinPercentChange returns the percent change of the coin over the period
+func coinPercentChange(coin *Coin, period string) float64 {
+	switch period {
+	case "1h":
+		return coin.PercentChange1H
+	case "7d":
+		return coin.PercentChange7D
+	}
+
+	return coin.PercentChange24H
+}
+
+// liquidCoins returns the coins with at least the market cap and 24 hour volume
+func liquidCoins(coins []*Coin, minMarketCap float64, minVolume float64) []*Coin {
+	var list []*Coin
+	for _, coin := range coins {
+		if coin != nil && coin.MarketCap >= minMarketCap && coin.Volume24H >= minVolume {
+			list = append(list, coin)
+		}
+	}
+
+	return list
+}
+
+// topMovers returns up to n coins with the biggest gains and the biggest losses over the period, the
+// highest ranked coin first on ties
+func topMovers(coins []*Coin, period string, n int) ([]*Coin, []*Coin) {
+	var gainers, losers []*Coin
+	for _, coin := range coins {
+		if change := coinPercentChange(coin, period); change > 0 {
+			gainers = append(gainers, coin)
+		} else if change < 0 {
+			losers = append(losers, coin)
+		}
+	}
+
+	sort.SliceStable(gainers, func(i, j int) bool {
+		a, b := coinPercentChange(gainers[i], period), coinPercentChange(gainers[j], period)
+		if a != b {
+			return a > b
+		}
+		return gainers[i].Rank < gainers[j].Rank
+	})
+	sort.SliceStable(losers, func(i, j int) bool {
+		a, b := coinPercentChange(losers[i], period), coinPercentChange(losers[j], period)
+		if a != b {
+			return a < b
+		}
+		return losers[i].Rank < losers[j].Rank
+	})
+
+	if len(gainers) > n {
+		gainers = gainers[:n]
+	}
+	if len(losers) > n {
+		losers = losers[:n]
+	}
+
+	return gainers, losers
+}
+
+// rankChanges returns up to n coins whose rank moved the most from their previous rank, keyed by coin name,
+// the highest ranked coin first on ties
+func rankChanges(coins []*Coin, previous map[string]int, n int) []rankChange {
+	var changes []rankChange
+	for _, coin := range coins {
+		from, ok := previous[coin.Name]
+		if !ok || from == 0 || coin.Rank == 0 || from == coin.Rank {
+			continue
+		}
+		changes = append(changes, rankChange{coin, from, coin.Rank})
+	}
+
+	abs := func(i int) int {
+		if i < 0 {
+			return -i
+		}
+		return i
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := abs(changes[i].Delta()), abs(changes[j].Delta())
+		if a != b {
+			return a > b
+		}
+		return changes[i].To < changes[j].To
+	})
+
+	if len(changes) > n {
+		changes = changes[:n]
+	}
+
+	return changes
+}
+
+// previousRanks returns the ranks of the coins before the last refresh, or at the end of the previous day
+func (ct *Cointop) previousRanks() map[string]int {
+	if ct.State.topMoversDaily {
+		return ct.yesterdayRanks()
+	}
+
+	ranks := make(map[string]int)
+	for _, coin := range ct.State.allCoins {
+		if coin != nil && coin.PreviousRank != 0 && !coin.RankChangedAt.Before(ct.State.lastRefresh) {
+			ranks[coin.Name] = coin.PreviousRank
+		}
+	}
+
+	return ranks
+}
+
+// loadRankHistory reads the ranks of the previous days from the file cache
+func (ct *Cointop) loadRankHistory() {
+	ct.debuglog("loadRankHistory()")
+	rankHistoryMux.Lock()
+	defer rankHistoryMux.Unlock()
+	filecache.Get(ct.CacheKey("rankHistory"), &ct.State.rankHistory)
+	if ct.State.rankHistory == nil {
+		ct.State.rankHistory = make(map[string]map[string]int)
+	}
+}
+
+// updateRankHistory sets the ranks of today to the ranks of the coins and writes the history to the file
+// cache. Only today and the latest previous day are kept
+func (ct *Cointop) updateRankHistory(coins []types.Coin) {
+	ct.debuglog("updateRankHistory()")
+	rankHistoryMux.Lock()
+	defer rankHistoryMux.Unlock()
+	if ct.State.rankHistory == nil {
+		ct.State.rankHistory = make(map[string]map[string]int)
+	}
+
+	today := time.Now().Format(rankHistoryDateFormat)
+	ranks, ok := ct.State.rankHistory[today]
+	if !ok {
+		ranks = make(map[string]int)
+		ct.State.rankHistory[today] = ranks
+	}
+	for _, coin := range coins {
+		if coin.Rank > 0 {
+			ranks[coin.Name] = coin.Rank
+		}
+	}
+
+	yesterday := previousRankHistoryDay(ct.State.rankHistory, today)
+	for day := range ct.State.rankHistory {
+		if day != today && day != yesterday {
+			delete(ct.State.rankHistory, day)
+		}
+	}
+
+	filecache.Set(ct.CacheKey("rankHistory"), ct.State.rankHistory, 7*24*time.Hour)
+}
+
+// yesterdayRanks returns the ranks of the latest day before today of the rank history
+func (ct *Cointop) yesterdayRanks() map[string]int {
+	rankHistoryMux.Lock()
+	defer rankHistoryMux.Unlock()
+	ranks := make(map[string]int)
+	day := previousRankHistoryDay(ct.State.rankHistory, time.Now().Format(rankHistoryDateFormat))
+	for name, rank := range ct.State.rankHistory[day] {
+		ranks[name] = rank
+	}
+
+	return ranks
+}
+
+// previousRankHistoryDay returns the latest day of the history before the day, or an empty string
+func previousRankHistoryDay(history map[string]map[string]int, day string) string {
+	previous := ""
+	for d := range history {
+		if d < day && d > previous {
+			previous = d
+		}
+	}
+
+	return previous
+}
+
+// topMoversCell returns the text padded or truncated to the width
+func topMoversCell(text string, width int) string {
+	if utf8.RuneCountInString(text) > width {
+		return string([]rune(text)[:width])
+	}
+
+	return text + strings.Repeat(" ", width-utf8.RuneCountInString(text))
+}
+
+func (ct *Cointop) updateTopMovers() {
+	ct.debuglog("updateTopMovers()")
+	title := "Top Movers"
+	header := ct.colorscheme.MenuHeader(fmt.Sprintf(" %s %s\n\n", title, pad.Left("[q] close ", ct.ClampedWidth()-len(title)-1, " ")))
+
+	var periods []string
+	for _, period := range topMoversPeriods {
+		label := fmt.Sprintf("[%s] %s", period[:1], period)
+		if period == ct.State.topMoversPeriod {
+			label = ct.colorscheme.MenuLabelActive(label)
+		}
+		periods = append(periods, label)
+	}
+	since := "last refresh"
+	if ct.State.topMoversDaily {
+		since = "yesterday"
+	}
+	helpline := fmt.Sprintf(" %s   [r] rank changes since %s\n", strings.Join(periods, " "), since)
+
+	symbol := currencySymbol(ct.State.currencyConversion)
+	var filters []string
+	if ct.State.topMoversMinMarketCap > 0 {
+		filters = append(filters, fmt.Sprintf("market cap >= %s", ct.formatCurrency(symbol, ct.formatCompact(ct.State.topMoversMinMarketCap))))
+	}
+	if ct.State.topMoversMinVolume > 0 {
+		filters = append(filters, fmt.Sprintf("24h volume >= %s", ct.formatCurrency(symbol, ct.formatCompact(ct.State.topMoversMinVolume))))
+	}
+	if len(filters) > 0 {
+		helpline = fmt.Sprintf("%s Only coins with %s\n", helpline, strings.Join(filters, " and "))
+	}
+
+	rows := ct.height() - 10
+	if rows > topMoversMaxRows {
+		rows = topMoversMaxRows
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	width := (ct.ClampedWidth() - 2) / 3
+
+	coins := liquidCoins(ct.State.allCoins, ct.State.topMoversMinMarketCap, ct.State.topMoversMinVolume)
+	gainers, losers := topMovers(coins, ct.State.topMoversPeriod, rows)
+	changes := rankChanges(coins, ct.previousRanks(), rows)
+
+	movers := func(list []*Coin, i int) string {
+		if i >= len(list) {
+			return topMoversCell("", width)
+		}
+		change := coinPercentChange(list[i], ct.State.topMoversPeriod)
+		percent := ct.formatPercent(change)
+		if change > 0 {
+			percent = "+" + percent
+		}
+		text := fmt.Sprintf(" %-8s %9s  %s", list[i].Symbol, percent, ct.formatCurrency(symbol, ct.formatNumber(list[i].Price)))
+		return ct.changeColor(change)(topMoversCell(text, width))
+	}
+
+	body := ct.colorscheme.MenuLabel(fmt.Sprintf("%s%s%s\n",
+		topMoversCell(fmt.Sprintf(" Gainers %s", ct.State.topMoversPeriod), width),
+		topMoversCell(fmt.Sprintf(" Losers %s", ct.State.topMoversPeriod), width),
+		topMoversCell(fmt.Sprintf(" Rank changes since %s", since), width),
+	))
+	for i := 0; i < rows; i++ {
+		rank := topMoversCell("", width)
+		if i < len(changes) {
+			change := changes[i]
+			text := fmt.Sprintf(" %-8s #%d -> #%d  (%+d)", change.Coin.Symbol, change.From, change.To, change.Delta())
+			rank = ct.changeColor(float64(change.Delta()))(topMoversCell(text, width))
+		} else if i == 0 {
+			rank = topMoversCell(fmt.Sprintf(" No rank changes since %s", since), width)
+		}
+		body = fmt.Sprintf("%s%s%s%s\n", body, movers(gainers, i), movers(losers, i), rank)
+	}
+
+	content := fmt.Sprintf("%s%s\n%s", header, helpline, body)
+	ct.Update(func() error {
+		if ct.Views.TopMovers.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.TopMovers.Backing().Clear()
+		ct.Views.TopMovers.Backing().Frame = true
+		fmt.Fprintln(ct.Views.TopMovers.Backing(), content)
+		return nil
+	})
+}
+
+func (ct *Cointop) showTopMovers() error {
+	ct.debuglog("showTopMovers()")
+	ct.State.topMoversVisible = true
+	ct.updateTopMovers()
+	ct.SetActiveView(ct.Views.TopMovers.Name())
+	return nil
+}
+
+func (ct *Cointop) hideTopMovers() error {
+	ct.debuglog("hideTopMovers()")
+	ct.State.topMoversVisible = false
+	ct.SetViewOnBottom(ct.Views.TopMovers.Name())
+	ct.SetActiveView(ct.Views.Table.Name())
+	ct.Update(func() error {
+		if ct.Views.TopMovers.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.TopMovers.Backing().Clear()
+		ct.Views.TopMovers.Backing().Frame = false
+		fmt.Fprintln(ct.Views.TopMovers.Backing(), "")
+		return nil
+	})
+	return nil
+}
+
+func (ct *Cointop) toggleTopMovers() error {
+	ct.debuglog("toggleTopMovers()")
+	if ct.State.topMoversVisible {
+		return ct.hideTopMovers()
+	}
+	return ct.showTopMovers()
+}
+
+// topMoversPeriodFn returns a function which ranks the top movers by the percent change over the period
+func (ct *Cointop) topMoversPeriodFn(period string) func() error {
+	return func() error {
+		ct.debuglog("topMoversPeriodFn()")
+		ct.State.topMoversPeriod = period
+		ct.updateTopMovers()
+		return nil
+	}
+}
+
+// toggleTopMoversDaily switches the rank changes between since the last refresh and since yesterday
+func (ct *Cointop) toggleTopMoversDaily() error {
+	ct.debuglog("toggleTopMoversDaily()")
+	ct.State.topMoversDaily = !ct.State.topMoversDaily
+	ct.updateTopMovers()
+	return nil
+}
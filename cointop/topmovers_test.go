@@ -0,0 +1,58 @@
+package cointop
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopMovers(t *testing.T) {
+	coins := []*Coin{
+		{Name: "Bitcoin", Symbol: "BTC", Rank: 1, PercentChange24H: 2, PercentChange7D: -5, MarketCap: 1e12, Volume24H: 1e10},
+		{Name: "Ethereum", Symbol: "ETH", Rank: 2, PercentChange24H: 8, PercentChange7D: 3, MarketCap: 4e11, Volume24H: 5e9},
+		{Name: "Solana", Symbol: "SOL", Rank: 5, PercentChange24H: -4, PercentChange7D: 12, MarketCap: 6e10, Volume24H: 2e9},
+		{Name: "Dogecoin", Symbol: "DOGE", Rank: 9, PercentChange24H: 2, MarketCap: 2e10, Volume24H: 1e9},
+		{Name: "Tiny", Symbol: "TINY", Rank: 900, PercentChange24H: 250, MarketCap: 1e5, Volume24H: 100},
+	}
+	symbols := func(list []*Coin) []string {
+		var ret []string
+		for _, coin := range list {
+			ret = append(ret, coin.Symbol)
+		}
+		return ret
+	}
+
+	gainers, losers := topMovers(coins, "24h", 3)
+	if want := []string{"TINY", "ETH", "BTC"}; !reflect.DeepEqual(symbols(gainers), want) {
+		t.Errorf("24h gainers = %v, want %v", symbols(gainers), want)
+	}
+	if want := []string{"SOL"}; !reflect.DeepEqual(symbols(losers), want) {
+		t.Errorf("24h losers = %v, want %v", symbols(losers), want)
+	}
+
+	gainers, losers = topMovers(liquidCoins(coins, 1e6, 1e6), "7d", 3)
+	if want := []string{"SOL", "ETH"}; !reflect.DeepEqual(symbols(gainers), want) {
+		t.Errorf("7d gainers = %v, want %v", symbols(gainers), want)
+	}
+	if want := []string{"BTC"}; !reflect.DeepEqual(symbols(losers), want) {
+		t.Errorf("7d losers = %v, want %v", symbols(losers), want)
+	}
+}
+
+func TestRankChanges(t *testing.T) {
+	coins := []*Coin{
+		{Name: "Bitcoin", Rank: 1},
+		{Name: "Solana", Rank: 4},
+		{Name: "Cardano", Rank: 5},
+		{Name: "Dogecoin", Rank: 9},
+	}
+	previous := map[string]int{"Bitcoin": 1, "Solana": 5, "Cardano": 4, "Dogecoin": 14}
+
+	var got []rankChange
+	for _, change := range rankChanges(coins, previous, 2) {
+		got = append(got, rankChange{nil, change.From, change.To})
+	}
+	want := []rankChange{{nil, 14, 9}, {nil, 5, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rankChanges() = %v, want %v", got, want)
+	}
+}
@@ -61,6 +61,13 @@ func (ct *Cointop) SetActiveView(v string) error {
 		ct.Views.SearchField.Backing().Clear()
 		ct.Views.SearchField.Backing().SetCursor(1, 0)
 		fmt.Fprintf(ct.Views.SearchField.Backing(), "%s", "/")
+	} else if v == ct.Views.FilterField.Name() {
+		text := fmt.Sprintf("|%s", ct.State.filterInput)
+		ct.Views.FilterField.Backing().Clear()
+		ct.Views.FilterField.Backing().SetCursor(len([]rune(text)), 0)
+		fmt.Fprintf(ct.Views.FilterField.Backing(), "%s", text)
+	} else if v == ct.Views.CommandField.Name() {
+		ct.setCommandInput("")
 	} else if v == ct.Views.Table.Name() {
 		ct.g.SetViewOnTop(ct.Views.Statusbar.Name())
 	}
@@ -68,6 +75,10 @@ func (ct *Cointop) SetActiveView(v string) error {
 		ct.g.SetViewOnTop(ct.Views.Input.Name())
 		ct.g.SetCurrentView(ct.Views.Input.Name())
 	}
+	if v == ct.Views.Calculator.Name() {
+		ct.g.SetViewOnTop(ct.Views.CalculatorInput.Name())
+		ct.g.SetCurrentView(ct.Views.CalculatorInput.Name())
+	}
 	return nil
 }
 
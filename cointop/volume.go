@@ -0,0 +1,157 @@
+package cointop
+
+import (
+	"fmt"
+
+	"github.com/cdyfng/coind/cointop/common/gizak/termui"
+)
+
+// VolumeView is structure for volume chart view
+type VolumeView struct {
+	*View
+}
+
+// NewVolumeView returns a new volume chart view
+func NewVolumeView() *VolumeView {
+	return &VolumeView{NewView("volume")}
+}
+
+// maxVolumeHeight is the maximum height of the volume chart
+const maxVolumeHeight = 10
+
+// chartVolumes returns the volume data of the coin over the selected chart range
+func (ct *Cointop) chartVolumes(symbol string, name string) []float64 {
+	ct.debuglog("chartVolumes()")
+	graph, err := ct.chartGraphData(symbol, name)
+	if err != nil {
+		return nil
+	}
+
+	return graph.Volumes
+}
+
+// volumeCells returns the cells of the volume bars aligned to the plotted columns of the chart
+func (ct *Cointop) volumeCells(chart *termui.LineChart, symbol string, name string) [][]termui.Cell {
+	ct.debuglog("volumeCells()")
+	height := ct.State.volumeHeight
+	if height == 0 {
+		return nil
+	}
+
+	volumes := ct.chartVolumes(symbol, name)
+	offset, n := chart.PlotColumns()
+	if len(volumes) == 0 || n == 0 {
+		return nil
+	}
+
+	data := make([]int, offset, offset+n)
+	for _, v := range bucketAverages(volumes, n) {
+		data = append(data, int(v))
+	}
+
+	// NOTE: the line color tags the full bar cells, which are drawn with a background color
+	spl := termui.NewSparkline()
+	spl.Data = data
+	spl.Height = height
+	spl.LineColor = termui.Attribute(1)
+
+	// NOTE: the sparkline is drawn below the first row
+	spls := termui.NewSparklines(spl)
+	spls.Border = false
+	spls.Height = height + 1
+	spls.SetWidth(ct.ClampedWidth())
+	spls.Align()
+
+	var points [][]termui.Cell
+	b := spls.Buffer()
+	for i := 1; i <= height; i++ {
+		var rowpoints []termui.Cell
+		for j := 0; j < spls.Width; j++ {
+			p := b.At(j, i)
+			if p.Ch == ' ' && p.Bg == spl.LineColor {
+				p.Ch = '█'
+			}
+			rowpoints = append(rowpoints, p)
+		}
+		points = append(points, rowpoints)
+	}
+
+	return points
+}
+
+// bucketAverages averages the values into n buckets
+func bucketAverages(values []float64, n int) []float64 {
+	if len(values) == 0 || n <= 0 {
+		return nil
+	}
+
+	buckets := make([]float64, n)
+	for i := range buckets {
+		from := i * len(values) / n
+		to := (i + 1) * len(values) / n
+		if to <= from {
+			to = from + 1
+		}
+		var sum float64
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		buckets[i] = sum / float64(to-from)
+	}
+
+	return buckets
+}
+
+// updateVolume renders the volume points. It must be called from an update
+func (ct *Cointop) updateVolume() {
+	ct.debuglog("updateVolume()")
+	if ct.Views.Volume.Backing() == nil {
+		return
+	}
+
+	var body string
+	for i := range ct.State.volumePoints {
+		var s string
+		for j := range ct.State.volumePoints[i] {
+			s = fmt.Sprintf("%s%c", s, ct.State.volumePoints[i][j].Ch)
+		}
+		body = fmt.Sprintf("%s%s\n", body, s)
+	}
+
+	ct.Views.Volume.Backing().Clear()
+	fmt.Fprint(ct.Views.Volume.Backing(), ct.colorscheme.ChartVolume(body))
+}
+
+// ShortenVolume decreases the volume chart height by one row. The volume chart is hidden at zero rows
+func (ct *Cointop) ShortenVolume() error {
+	ct.debuglog("ShortenVolume()")
+	candidate := ct.State.volumeHeight - 1
+	if candidate < 0 || ct.State.hideChart {
+		return nil
+	}
+	ct.State.volumeHeight = candidate
+
+	if err := ct.Save(); err != nil {
+		return err
+	}
+
+	go ct.UpdateChart()
+	return nil
+}
+
+// EnlargeVolume increases the volume chart height by one row
+func (ct *Cointop) EnlargeVolume() error {
+	ct.debuglog("EnlargeVolume()")
+	candidate := ct.State.volumeHeight + 1
+	if candidate > maxVolumeHeight || ct.State.hideChart {
+		return nil
+	}
+	ct.State.volumeHeight = candidate
+
+	if err := ct.Save(); err != nil {
+		return err
+	}
+
+	go ct.UpdateChart()
+	return nil
+}
@@ -0,0 +1,260 @@
+package cointop
+
+import (
+	"fmt"
+	"sort"
+
+	color "github.com/cdyfng/coind/cointop/common/color"
+	"github.com/cdyfng/coind/cointop/common/pad"
+)
+
+// WatchlistsMenuView is structure for watchlists menu view
+type WatchlistsMenuView struct {
+	*View
+}
+
+// NewWatchlistsMenuView returns a new watchlists menu view
+func NewWatchlistsMenuView() *WatchlistsMenuView {
+	return &WatchlistsMenuView{NewView("watchlistsmenu")}
+}
+
+// defaultWatchlist is the name of the favorites list, which is saved in the favorites section of the config
+const defaultWatchlist = "favorites"
+
+// watchlistNames returns the names of the watchlists; the default list first, followed by the named lists in order
+func (ct *Cointop) watchlistNames() []string {
+	var names []string
+	for name := range ct.State.watchlists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return append([]string{defaultWatchlist}, names...)
+}
+
+// watchlist returns the coin names of the watchlist
+func (ct *Cointop) watchlist(name string) map[string]bool {
+	if name == defaultWatchlist {
+		return ct.State.favorites
+	}
+
+	return ct.State.watchlists[name]
+}
+
+// inWatchlist returns true if the coin is in the watchlist
+func (ct *Cointop) inWatchlist(name string, coin *Coin) bool {
+	if coin == nil {
+		return false
+	}
+
+	return ct.watchlist(name)[coin.Name]
+}
+
+// toggleWatchlistCoin adds the coin to the watchlist, or removes it if it's already in it.
+// The named list is created when the first coin is added and kept when it's empty
+func (ct *Cointop) toggleWatchlistCoin(name string, coin *Coin) error {
+	ct.debuglog("toggleWatchlistCoin()")
+	if coin == nil {
+		return nil
+	}
+
+	list := ct.watchlist(name)
+	if list == nil {
+		list = make(map[string]bool)
+		ct.State.watchlists[name] = list
+	}
+
+	if list[coin.Name] {
+		delete(list, coin.Name)
+	} else {
+		list[coin.Name] = true
+	}
+	if name == defaultWatchlist {
+		coin.Favorite = list[coin.Name]
+	}
+
+	if err := ct.Save(); err != nil {
+		return err
+	}
+
+	go ct.UpdateTable()
+	return nil
+}
+
+// nextWatchlist cycles the table through the watchlists and back to all the coins
+func (ct *Cointop) nextWatchlist() error {
+	ct.debuglog("nextWatchlist()")
+	ct.State.portfolioVisible = false
+	names := ct.watchlistNames()
+	if !ct.State.filterByFavorites {
+		ct.State.filterByFavorites = true
+		ct.State.selectedWatchlist = names[0]
+	} else {
+		ct.State.filterByFavorites = false
+		for i, name := range names {
+			if name == ct.State.selectedWatchlist && i+1 < len(names) {
+				ct.State.filterByFavorites = true
+				ct.State.selectedWatchlist = names[i+1]
+				break
+			}
+		}
+	}
+
+	if ct.State.filterByFavorites {
+		ct.UpdateStatusbar(fmt.Sprintf("Watchlist: %s", ct.State.selectedWatchlist))
+	} else {
+		ct.State.selectedWatchlist = defaultWatchlist
+		ct.UpdateStatusbar("Watchlist: all coins")
+	}
+
+	go ct.UpdateTable()
+	return nil
+}
+
+func (ct *Cointop) updateWatchlistsMenu() {
+	ct.debuglog("updateWatchlistsMenu()")
+	coin := ct.State.watchlistsMenuCoin
+	title := "Watchlists"
+	if coin != nil {
+		title = fmt.Sprintf("Watchlists for %s (%s)", coin.Name, coin.Symbol)
+	}
+	header := ct.colorscheme.MenuHeader(fmt.Sprintf(" %s %s\n\n", title, pad.Left("[q] close menu ", ct.ClampedWidth()-len(title)-1, " ")))
+	helpline := " [space] add/remove coin [enter] show list [d] delete list\n Enter :watchlist <name> to add the coin to a new list\n\n"
+
+	var body string
+	for i, name := range ct.watchlistNames() {
+		check := " "
+		if ct.inWatchlist(name, coin) {
+			check = "x"
+		}
+		label := ct.colorscheme.MenuLabel(fmt.Sprintf("%-20s", name))
+		cursor := " "
+		if i == ct.State.watchlistsMenuIndex {
+			cursor = ct.colorscheme.MenuLabelActive(color.Bold(">"))
+			label = ct.colorscheme.MenuLabelActive(color.Bold(fmt.Sprintf("%-20s", name)))
+		}
+		count := ct.colorscheme.Menu(fmt.Sprintf("%4d coins", len(ct.watchlist(name))))
+		body = fmt.Sprintf("%s %s [%s] %s %s\n", body, cursor, check, label, count)
+	}
+
+	content := fmt.Sprintf("%s%s%s", header, helpline, body)
+	ct.Update(func() error {
+		if ct.Views.WatchlistsMenu.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.WatchlistsMenu.Backing().Clear()
+		ct.Views.WatchlistsMenu.Backing().Frame = true
+		fmt.Fprintln(ct.Views.WatchlistsMenu.Backing(), content)
+		return nil
+	})
+}
+
+func (ct *Cointop) showWatchlistsMenu() error {
+	ct.debuglog("showWatchlistsMenu()")
+	ct.State.watchlistsMenuCoin = ct.HighlightedRowCoin()
+	ct.State.watchlistsMenuIndex = 0
+	ct.State.watchlistsMenuVisible = true
+	ct.updateWatchlistsMenu()
+	ct.SetActiveView(ct.Views.WatchlistsMenu.Name())
+	return nil
+}
+
+func (ct *Cointop) hideWatchlistsMenu() error {
+	ct.debuglog("hideWatchlistsMenu()")
+	ct.State.watchlistsMenuVisible = false
+	ct.SetViewOnBottom(ct.Views.WatchlistsMenu.Name())
+	ct.SetActiveView(ct.Views.Table.Name())
+	ct.Update(func() error {
+		if ct.Views.WatchlistsMenu.Backing() == nil {
+			return nil
+		}
+
+		ct.Views.WatchlistsMenu.Backing().Clear()
+		ct.Views.WatchlistsMenu.Backing().Frame = false
+		fmt.Fprintln(ct.Views.WatchlistsMenu.Backing(), "")
+		return nil
+	})
+	return nil
+}
+
+func (ct *Cointop) toggleWatchlistsMenu() error {
+	ct.debuglog("toggleWatchlistsMenu()")
+	if ct.State.watchlistsMenuVisible {
+		return ct.hideWatchlistsMenu()
+	}
+	return ct.showWatchlistsMenu()
+}
+
+// watchlistsMenuCursorFn returns a function which moves the menu selection by the given offset
+func (ct *Cointop) watchlistsMenuCursorFn(offset int) func() error {
+	return func() error {
+		ct.debuglog("watchlistsMenuCursor()")
+		n := len(ct.watchlistNames())
+		ct.State.watchlistsMenuIndex += offset
+		if ct.State.watchlistsMenuIndex >= n {
+			ct.State.watchlistsMenuIndex = n - 1
+		}
+		if ct.State.watchlistsMenuIndex < 0 {
+			ct.State.watchlistsMenuIndex = 0
+		}
+		ct.updateWatchlistsMenu()
+		return nil
+	}
+}
+
+// watchlistsMenuToggle adds the coin to the selected watchlist, or removes it
+func (ct *Cointop) watchlistsMenuToggle() error {
+	ct.debuglog("watchlistsMenuToggle()")
+	names := ct.watchlistNames()
+	if ct.State.watchlistsMenuIndex >= len(names) {
+		return nil
+	}
+	if err := ct.toggleWatchlistCoin(names[ct.State.watchlistsMenuIndex], ct.State.watchlistsMenuCoin); err != nil {
+		return err
+	}
+
+	ct.updateWatchlistsMenu()
+	return nil
+}
+
+// watchlistsMenuDelete deletes the selected watchlist. The favorites can't be deleted
+func (ct *Cointop) watchlistsMenuDelete() error {
+	ct.debuglog("watchlistsMenuDelete()")
+	names := ct.watchlistNames()
+	if ct.State.watchlistsMenuIndex >= len(names) {
+		return nil
+	}
+	name := names[ct.State.watchlistsMenuIndex]
+	if name == defaultWatchlist {
+		return nil
+	}
+
+	delete(ct.State.watchlists, name)
+	if ct.State.selectedWatchlist == name {
+		ct.State.filterByFavorites = false
+		ct.State.selectedWatchlist = defaultWatchlist
+	}
+	if err := ct.Save(); err != nil {
+		return err
+	}
+
+	go ct.UpdateTable()
+	// NOTE: the selection is kept in range after the list is deleted
+	return ct.watchlistsMenuCursorFn(0)()
+}
+
+// watchlistsMenuShow shows the coins of the selected watchlist in the table
+func (ct *Cointop) watchlistsMenuShow() error {
+	ct.debuglog("watchlistsMenuShow()")
+	names := ct.watchlistNames()
+	if ct.State.watchlistsMenuIndex < len(names) {
+		ct.State.portfolioVisible = false
+		ct.State.filterByFavorites = true
+		ct.State.selectedWatchlist = names[ct.State.watchlistsMenuIndex]
+	}
+
+	ct.hideWatchlistsMenu()
+	go ct.UpdateTable()
+	return nil
+}